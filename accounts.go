@@ -0,0 +1,73 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// Account is an account holding a single currency.
+type Account struct {
+	ID       string
+	Name     string
+	Currency string
+}
+
+type account struct {
+	Error    string `json:"error"`
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+// CreateAccount creates an additional account for currency with the given
+// name. Use its ID as the base or counter account when placing orders.
+func (c *Client) CreateAccount(currency, name string) (*Account, error) {
+	return c.CreateAccountContext(context.Background(), currency, name)
+}
+
+// CreateAccountContext is like CreateAccount but takes a context.
+func (c *Client) CreateAccountContext(ctx context.Context,
+	currency, name string) (*Account, error) {
+	form := url.Values{
+		"currency": {currency},
+		"name":     {name},
+	}
+	var r account
+	err := c.call(ctx, "POST", "/api/1/accounts", form, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, remoteError("/api/1/accounts", r.Error)
+	}
+	return &Account{ID: r.ID, Name: r.Name, Currency: r.Currency}, nil
+}
+
+type accountName struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+}
+
+// UpdateAccountName renames an account.
+func (c *Client) UpdateAccountName(id, name string) error {
+	return c.UpdateAccountNameContext(context.Background(), id, name)
+}
+
+// UpdateAccountNameContext is like UpdateAccountName but takes a context.
+func (c *Client) UpdateAccountNameContext(ctx context.Context,
+	id, name string) error {
+	if !isValidPathID(id) {
+		return errors.New("invalid account id")
+	}
+	path := "/api/1/accounts/" + id + "/name"
+	var r accountName
+	err := c.call(ctx, "PUT", path, url.Values{"name": {name}}, &r)
+	if err != nil {
+		return err
+	}
+	if r.Error != "" {
+		return remoteError(path, r.Error)
+	}
+	return nil
+}
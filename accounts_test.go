@@ -0,0 +1,57 @@
+package bitx
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateAccount(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.FormValue("currency") != "XBT" ||
+			r.FormValue("name") != "Strategy A" {
+			t.Errorf("Unexpected request %s %v", r.Method, r.Form)
+		}
+		w.Write([]byte(`{"id":"319232323","name":"Strategy A","currency":"XBT"}`))
+	})
+	a, err := c.CreateAccount("XBT", "Strategy A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != "319232323" || a.Name != "Strategy A" || a.Currency != "XBT" {
+		t.Errorf("Unexpected account %+v", a)
+	}
+}
+
+func TestUpdateAccountName(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/1/accounts/319232323/name" ||
+			r.FormValue("name") != "Strategy B" {
+			t.Errorf("Unexpected request %s %s %v", r.Method, r.URL.Path, r.Form)
+		}
+		w.Write([]byte(`{"success":true}`))
+	})
+	if err := c.UpdateAccountName("319232323", "Strategy B"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBalancesAssets(t *testing.T) {
+	var assets []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assets = r.URL.Query()["assets"]
+		w.Write([]byte(`{"balance":[{"account_id":"1","asset":"XBT",` +
+			`"balance":"1","reserved":"0","unconfirmed":"0"},` +
+			`{"account_id":"2","asset":"ETH","balance":"2","reserved":"0",` +
+			`"unconfirmed":"0"}]}`))
+	})
+	bl, err := c.Balances("XBT", "ETH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assets) != 2 || assets[0] != "XBT" || assets[1] != "ETH" {
+		t.Errorf("Unexpected assets filter %v", assets)
+	}
+	if len(bl) != 2 || bl[1].AccountID != "2" {
+		t.Errorf("Unexpected balances %+v", bl)
+	}
+}
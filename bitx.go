@@ -4,15 +4,16 @@ package bitx
 
 import (
 	"bytes"
+	"context"
 	_ "crypto/sha512"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -23,12 +24,59 @@ var defaultBaseURL = url.URL{Scheme: "https", Host: "api.mybitx.com"}
 type Client struct {
 	apiKeyID, apiKeySecret string
 	baseURL                url.URL
+	userAgent              string
+	timeout                time.Duration
+	retry                  RetryPolicy
+
+	limiters          map[EndpointClass]*tokenBucket
+	rateLimitFailFast bool
+
+	lenientDecoding bool
+
+	validateMarkets bool
+	roundToMarkets  bool
+	marketsMu       sync.Mutex
+	markets         map[string]MarketInfo
+	marketsFetched  time.Time
+	marketsFetching chan struct{} // closed when the current fetch finishes
+
+	middleware []Middleware
+	handler    Handler
+
+	httpClient *http.Client
 }
 
 // Pass an empty string for the api_key_id if you will only access the public
 // API.
-func NewClient(apiKeyID, apiKeySecret string) *Client {
-	return &Client{apiKeyID, apiKeySecret, defaultBaseURL}
+// The client reuses connections between requests and is safe for concurrent
+// use by multiple goroutines.
+func NewClient(apiKeyID, apiKeySecret string, opts ...ClientOption) *Client {
+	c := &Client{
+		apiKeyID:     apiKeyID,
+		apiKeySecret: apiKeySecret,
+		baseURL:      defaultBaseURL,
+		userAgent:    userAgent,
+		retry:        DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Transport: http.DefaultTransport}
+	}
+	if c.timeout > 0 {
+		// Don't modify a client that was passed in by the caller.
+		hc := *c.httpClient
+		hc.Timeout = c.timeout
+		c.httpClient = &hc
+	}
+
+	c.handler = c.do
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		c.handler = c.middleware[i](c.handler)
+	}
+	return c
 }
 
 type errorResp struct {
@@ -36,110 +84,247 @@ type errorResp struct {
 	ErrorCode string `json:"error_code"`
 }
 
-func (c *Client) call(method, path string, params url.Values,
-	result interface{}) error {
+func (c *Client) call(ctx context.Context, method, path string,
+	params url.Values, result interface{}) error {
+	if method != "GET" && method != "POST" && method != "PUT" &&
+		method != "PATCH" && method != "DELETE" {
+		return errors.New("Unsupported method")
+	}
+
+	var (
+		resp *Response
+		err  error
+	)
+	for attempt := 1; ; attempt++ {
+		req := &Request{
+			Method:  method,
+			Path:    path,
+			Params:  redactParams(params),
+			Header:  make(http.Header),
+			Attempt: attempt,
+			params:  params,
+		}
+		resp, err = c.handler(ctx, req)
+		if err == nil || !c.shouldRetry(ctx, method, attempt, err) {
+			break
+		}
+
+		wait := c.retry.backoff(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp.Header); ok {
+				wait = d
+				if c.retry.MaxBackoff > 0 && wait > c.retry.MaxBackoff {
+					wait = c.retry.MaxBackoff
+				}
+			}
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("bitx: no response from middleware")
+	}
+
+	return json.Unmarshal(resp.Body, &result)
+}
+
+// do makes a single attempt at a request. It is the innermost Handler of the
+// middleware chain. The response is returned along with any error if one was
+// received.
+func (c *Client) do(ctx context.Context, r *Request) (*Response, error) {
+	if err := c.waitRateLimit(ctx, r.Path); err != nil {
+		return nil, err
+	}
+
 	u := c.baseURL
-	u.Path = path
+	u.Path = r.Path
 
 	var body *bytes.Reader
-	if method == "GET" {
-		u.RawQuery = params.Encode()
-		body = bytes.NewReader(nil)
-	} else if method == "POST" || method == "PUT" || method == "PATCH" {
-		body = bytes.NewReader([]byte(params.Encode()))
-	} else if method == "DELETE" {
+	if r.Method == "GET" {
+		u.RawQuery = r.params.Encode()
 		body = bytes.NewReader(nil)
+	} else if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
+		body = bytes.NewReader([]byte(r.params.Encode()))
 	} else {
-		return errors.New("Unsupported method")
+		body = bytes.NewReader(nil)
 	}
 
-	req, err := http.NewRequest(method, u.String(), body)
+	req, err := http.NewRequestWithContext(ctx, r.Method, u.String(), body)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
 	}
 	if c.apiKeyID != "" {
 		req.SetBasicAuth(c.apiKeyID, c.apiKeySecret)
 	}
-	req.Header.Add("User-Agent", userAgent)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	r, err := (&http.Client{}).Do(req)
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	start := time.Now()
+	hr, err := c.httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer r.Body.Close()
-
-	if r.StatusCode != http.StatusOK {
-		body, _ := ioutil.ReadAll(r.Body)
-		return errors.New(fmt.Sprintf(
-			"BitX error %d: %s: %s",
-			r.StatusCode, r.Status, string(body)))
+	defer hr.Body.Close()
+
+	data, err := ioutil.ReadAll(hr.Body)
+	resp := &Response{
+		StatusCode: hr.StatusCode,
+		Header:     hr.Header,
+		Body:       data,
+		Latency:    time.Since(start),
+	}
+	if hr.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: hr.StatusCode, Path: r.Path}
+		var errResult errorResp
+		if json.Unmarshal(data, &errResult) == nil &&
+			(errResult.Error != "" || errResult.ErrorCode != "") {
+			apiErr.Code = errResult.ErrorCode
+			apiErr.Message = errResult.Error
+		} else if len(data) > 0 {
+			apiErr.Message = string(data)
+		} else {
+			apiErr.Message = hr.Status
+		}
+		return resp, apiErr
 	}
-
-	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return err
+		return resp, err
 	}
 
 	var errResult errorResp
 	if err := json.Unmarshal(data, &errResult); err != nil {
-		return err
+		return resp, err
 	}
-
 	if errResult.Error != "" || errResult.ErrorCode != "" {
-		return fmt.Errorf("bitx: remote error %s %s", errResult.ErrorCode, errResult.Error)
+		return resp, &APIError{
+			StatusCode: hr.StatusCode,
+			Code:       errResult.ErrorCode,
+			Message:    errResult.Error,
+			Path:       r.Path,
+		}
 	}
-
-	return json.Unmarshal(data, &result)
+	return resp, nil
 }
 
 type ticker struct {
 	Error     string `json:"error"`
+	Pair      string `json:"pair"`
 	Timestamp int64  `json:"timestamp"`
 	Bid       string `json:"bid"`
 	Ask       string `json:"ask"`
 	Last      string `json:"last_trade"`
 	Volume24H string `json:"rolling_24_hour_volume"`
+	Status    string `json:"status"`
 }
 
 type Ticker struct {
 	Timestamp                 time.Time
 	Bid, Ask, Last, Volume24H float64
+
+	// Exact values of the fields above.
+	BidDecimal, AskDecimal, LastDecimal, Volume24HDecimal Decimal
+
+	Pair string
+
+	// Status is the trading status of the market.
+	Status TradingStatus
+}
+
+func parseTicker(d *decoder, r ticker) Ticker {
+	bid := d.decimal("bid", r.Bid)
+	ask := d.decimal("ask", r.Ask)
+	last := d.decimal("last_trade", r.Last)
+	volume24h := d.decimal("rolling_24_hour_volume", r.Volume24H)
+	return Ticker{
+		Timestamp:        fromMillis(r.Timestamp),
+		Bid:              bid.Float64(),
+		Ask:              ask.Float64(),
+		Last:             last.Float64(),
+		Volume24H:        volume24h.Float64(),
+		BidDecimal:       bid,
+		AskDecimal:       ask,
+		LastDecimal:      last,
+		Volume24HDecimal: volume24h,
+		Pair:             r.Pair,
+		Status:           TradingStatus(r.Status),
+	}
 }
 
 // Returns the latest ticker indicators for the given currency pair..
 func (c *Client) Ticker(pair string) (Ticker, error) {
+	return c.TickerContext(context.Background(), pair)
+}
+
+// TickerContext is like Ticker but takes a context.
+func (c *Client) TickerContext(ctx context.Context, pair string) (Ticker, error) {
 	var r ticker
-	err := c.call("GET", "/api/1/ticker", url.Values{"pair": {pair}}, &r)
+	err := c.call(ctx, "GET", "/api/1/ticker", url.Values{"pair": {pair}}, &r)
 	if err != nil {
 		return Ticker{}, err
 	}
 	if r.Error != "" {
-		return Ticker{}, errors.New("BitX error: " + r.Error)
+		return Ticker{}, remoteError("/api/1/ticker", r.Error)
 	}
 
-	t := time.Unix(r.Timestamp/1000, 0)
-
-	bid, err := strconv.ParseFloat(r.Bid, 64)
-	if err != nil {
-		return Ticker{}, err
+	d := c.newDecoder("/api/1/ticker")
+	t := parseTicker(d, r)
+	if d.err != nil {
+		return Ticker{}, d.err
 	}
-
-	ask, err := strconv.ParseFloat(r.Ask, 64)
-	if err != nil {
-		return Ticker{}, err
+	if t.Pair == "" {
+		t.Pair = pair
 	}
+	return t, nil
+}
 
-	last, err := strconv.ParseFloat(r.Last, 64)
-	if err != nil {
-		return Ticker{}, err
-	}
+type tickers struct {
+	Error   string   `json:"error"`
+	Tickers []ticker `json:"tickers"`
+}
 
-	volume24h, err := strconv.ParseFloat(r.Volume24H, 64)
+// Tickers returns the latest ticker indicators of the given currency pairs,
+// or of all markets if no pairs are given, keyed by pair. It makes a single
+// request.
+func (c *Client) Tickers(pairs ...string) (map[string]Ticker, error) {
+	return c.TickersContext(context.Background(), pairs...)
+}
+
+// TickersContext is like Tickers but takes a context.
+func (c *Client) TickersContext(ctx context.Context,
+	pairs ...string) (map[string]Ticker, error) {
+	params := make(url.Values)
+	for _, p := range pairs {
+		params.Add("pair", p)
+	}
+	var r tickers
+	err := c.call(ctx, "GET", "/api/1/tickers", params, &r)
 	if err != nil {
-		return Ticker{}, err
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, remoteError("/api/1/tickers", r.Error)
 	}
 
-	return Ticker{t, bid, ask, last, volume24h}, nil
+	d := c.newDecoder("/api/1/tickers")
+	res := make(map[string]Ticker, len(r.Tickers))
+	for _, tr := range r.Tickers {
+		res[tr.Pair] = parseTicker(d, tr)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return res, nil
 }
 
 type orderbookEntry struct {
@@ -155,15 +340,18 @@ type orderbook struct {
 
 type OrderBookEntry struct {
 	Price, Volume float64
+
+	// Exact values of the fields above.
+	PriceDecimal, VolumeDecimal Decimal
 }
 
-func convert(entries []orderbookEntry) (r []OrderBookEntry) {
+func convert(d *decoder, entries []orderbookEntry) (r []OrderBookEntry) {
 	r = make([]OrderBookEntry, len(entries))
 	for i, e := range entries {
-		price, _ := strconv.ParseFloat(e.Price, 64)
-		volume, _ := strconv.ParseFloat(e.Volume, 64)
-		r[i].Price = price
-		r[i].Volume = volume
+		r[i].PriceDecimal = d.decimal("price", e.Price)
+		r[i].VolumeDecimal = d.decimal("volume", e.Volume)
+		r[i].Price = r[i].PriceDecimal.Float64()
+		r[i].Volume = r[i].VolumeDecimal.Float64()
 	}
 	return r
 }
@@ -172,23 +360,36 @@ func convert(entries []orderbookEntry) (r []OrderBookEntry) {
 // pair.
 func (c *Client) OrderBook(pair string) (
 	bids, asks []OrderBookEntry, err error) {
+	return c.OrderBookContext(context.Background(), pair)
+}
+
+// OrderBookContext is like OrderBook but takes a context.
+func (c *Client) OrderBookContext(ctx context.Context, pair string) (
+	bids, asks []OrderBookEntry, err error) {
 
 	var r orderbook
-	err = c.call("GET", "/api/1/orderbook", url.Values{"pair": {pair}}, &r)
+	err = c.call(ctx, "GET", "/api/1/orderbook", url.Values{"pair": {pair}}, &r)
 	if err != nil {
 		return nil, nil, err
 	}
 	if r.Error != "" {
-		return nil, nil, errors.New("BitX error: " + r.Error)
+		return nil, nil, remoteError("/api/1/orderbook", r.Error)
 	}
 
-	return convert(r.Bids), convert(r.Asks), nil
+	d := c.newDecoder("/api/1/orderbook")
+	bids, asks = convert(d, r.Bids), convert(d, r.Asks)
+	if d.err != nil {
+		return nil, nil, d.err
+	}
+	return bids, asks, nil
 }
 
 type trade struct {
+	Sequence  int64  `json:"sequence"`
 	Timestamp int64  `json:"timestamp"`
 	Price     string `json:"price"`
 	Volume    string `json:"volume"`
+	IsBuy     bool   `json:"is_buy"`
 }
 
 type trades struct {
@@ -199,26 +400,65 @@ type trades struct {
 type Trade struct {
 	Timestamp     time.Time
 	Price, Volume float64
+
+	// Exact values of the fields above.
+	PriceDecimal, VolumeDecimal Decimal
+
+	// IsBuy is true if the taker was the buyer.
+	IsBuy bool
+
+	// Sequence is the trade's position in the market's trade history.
+	Sequence int64
 }
 
 // Returns a list of the most recent trades for the given currency pair.
 func (c *Client) Trades(pair string) ([]Trade, error) {
+	return c.TradesContext(context.Background(), pair)
+}
+
+// TradesContext is like Trades but takes a context.
+func (c *Client) TradesContext(ctx context.Context, pair string) ([]Trade, error) {
+	return c.trades(ctx, url.Values{"pair": {pair}})
+}
+
+// TradesSince returns a page of trades for the given currency pair made at or
+// after since. Use TradesIter to page through all trades since a time.
+func (c *Client) TradesSince(pair string, since time.Time) ([]Trade, error) {
+	return c.TradesSinceContext(context.Background(), pair, since)
+}
+
+// TradesSinceContext is like TradesSince but takes a context.
+func (c *Client) TradesSinceContext(ctx context.Context, pair string,
+	since time.Time) ([]Trade, error) {
+	return c.trades(ctx, url.Values{
+		"pair":  {pair},
+		"since": {strconv.FormatInt(since.UnixNano()/int64(time.Millisecond), 10)},
+	})
+}
+
+func (c *Client) trades(ctx context.Context, params url.Values) ([]Trade, error) {
 	var r trades
-	err := c.call("GET", "/api/1/trades", url.Values{"pair": {pair}}, &r)
+	err := c.call(ctx, "GET", "/api/1/trades", params, &r)
 	if err != nil {
 		return nil, err
 	}
 	if r.Error != "" {
-		return nil, errors.New("BitX error: " + r.Error)
+		return nil, remoteError("/api/1/trades", r.Error)
 	}
 
+	d := c.newDecoder("/api/1/trades")
 	tr := make([]Trade, len(r.Trades))
 	for i, t := range r.Trades {
-		tr[i].Timestamp = time.Unix(t.Timestamp/1000, 0)
-		price, _ := strconv.ParseFloat(t.Price, 64)
-		volume, _ := strconv.ParseFloat(t.Volume, 64)
-		tr[i].Price = price
-		tr[i].Volume = volume
+		tr[i].Timestamp = fromMillis(t.Timestamp)
+		tr[i].PriceDecimal = d.decimal("price", t.Price)
+		tr[i].VolumeDecimal = d.decimal("volume", t.Volume)
+		tr[i].Price = tr[i].PriceDecimal.Float64()
+		tr[i].Volume = tr[i].VolumeDecimal.Float64()
+		tr[i].IsBuy = t.IsBuy
+		tr[i].Sequence = t.Sequence
+	}
+	if d.err != nil {
+		return nil, d.err
 	}
 	return tr, nil
 }
@@ -233,34 +473,54 @@ type OrderType string
 const BID = OrderType("BID")
 const ASK = OrderType("ASK")
 
+// floatScale is the number of decimal places that PostOrder keeps of float
+// volumes and prices.
+const floatScale = 8
+
 // Create a new trade order.
 // Specify zero for baseAccountID and counterAccountID to use your default
 // accounts.
+// The volume is truncated and the price rounded to 8 decimal places. Use
+// PostOrderDecimal to send exact values.
 func (c *Client) PostOrder(pair string, order_type OrderType,
 	volume, price float64,
 	baseAccountID, counterAccountID string) (string, error) {
-	form := make(url.Values)
-	form.Add("volume", fmt.Sprintf("%f", volume))
-	form.Add("price", fmt.Sprintf("%f", price))
-	form.Add("pair", pair)
-	form.Add("type", string(order_type))
-	if baseAccountID != "" {
-		form.Add("base_account_id", baseAccountID)
-	}
-	if counterAccountID != "" {
-		form.Add("counter_account_id", counterAccountID)
-	}
+	return c.PostOrderContext(context.Background(), pair, order_type,
+		volume, price, baseAccountID, counterAccountID)
+}
 
-	var r postorder
-	err := c.call("POST", "/api/1/postorder", form, &r)
-	if err != nil {
-		return "", err
-	}
-	if r.Error != "" {
-		return "", errors.New("BitX error: " + r.Error)
-	}
+// PostOrderContext is like PostOrder but takes a context.
+func (c *Client) PostOrderContext(ctx context.Context, pair string,
+	order_type OrderType, volume, price float64,
+	baseAccountID, counterAccountID string) (string, error) {
+	// Floats such as 0.1+0.2 would otherwise be sent with 17 significant
+	// digits. Truncating the volume never makes it exceed the balance.
+	return c.PostOrderDecimalContext(ctx, pair, order_type,
+		NewDecimalFromFloat64(volume).Truncate(floatScale),
+		NewDecimalFromFloat64(price).ToScale(floatScale),
+		baseAccountID, counterAccountID)
+}
 
-	return r.OrderId, nil
+// PostOrderDecimal is like PostOrder but takes exact volume and price values.
+func (c *Client) PostOrderDecimal(pair string, order_type OrderType,
+	volume, price Decimal,
+	baseAccountID, counterAccountID string) (string, error) {
+	return c.PostOrderDecimalContext(context.Background(), pair, order_type,
+		volume, price, baseAccountID, counterAccountID)
+}
+
+// PostOrderDecimalContext is like PostOrderDecimal but takes a context.
+func (c *Client) PostOrderDecimalContext(ctx context.Context, pair string,
+	order_type OrderType, volume, price Decimal,
+	baseAccountID, counterAccountID string) (string, error) {
+	return c.PostLimitOrderContext(ctx, LimitOrder{
+		Pair:             pair,
+		Type:             order_type,
+		Volume:           volume,
+		Price:            price,
+		BaseAccountID:    baseAccountID,
+		CounterAccountID: counterAccountID,
+	})
 }
 
 type order struct {
@@ -275,6 +535,16 @@ type order struct {
 	Counter           string `json:"counter"`
 	FeeBase           string `json:"fee_base"`
 	FeeCounter        string `json:"fee_counter"`
+
+	Pair                string `json:"pair"`
+	CompletedTimestamp  int64  `json:"completed_timestamp"`
+	ExpirationTimestamp int64  `json:"expiration_timestamp"`
+	StopPrice           string `json:"stop_price"`
+	StopDirection       string `json:"stop_direction"`
+	TimeInForce         string `json:"time_in_force"`
+	ClientOrderID       string `json:"client_order_id"`
+	BaseAccountID       string `json:"base_account_id"`
+	CounterAccountID    string `json:"counter_account_id"`
 }
 
 type orders struct {
@@ -284,7 +554,14 @@ type orders struct {
 
 type OrderState string
 
+// Awaiting is the state of a stop-limit order whose stop price hasn't been
+// reached yet.
+const Awaiting = OrderState("AWAITING")
 const Pending = OrderState("PENDING")
+
+// Complete is the state of an order which is no longer open. The API uses it
+// for orders which were filled as well as for orders which were stopped or
+// expired before being filled; use IsCancelled to tell them apart.
 const Complete = OrderState("COMPLETE")
 
 type Order struct {
@@ -296,49 +573,125 @@ type Order struct {
 	LimitVolume         float64
 	Base, Counter       float64
 	FeeBase, FeeCounter float64
+
+	// Exact values of the fields above.
+	LimitPriceDecimal, LimitVolumeDecimal Decimal
+	BaseDecimal, CounterDecimal           Decimal
+	FeeBaseDecimal, FeeCounterDecimal     Decimal
+
+	Pair string
+
+	// CompletedAt is zero if the order is still open. ExpiresAt is zero if
+	// the order doesn't expire.
+	CompletedAt time.Time
+	ExpiresAt   time.Time
+
+	// StopPrice and StopDirection are only set for stop-limit orders.
+	StopPrice     Decimal
+	StopDirection StopDirection
+
+	TimeInForce   TimeInForce
+	ClientOrderID string
+
+	BaseAccountID, CounterAccountID string
 }
 
-func atofloat64(s string) float64 {
-	f, _ := strconv.ParseFloat(s, 64)
-	return f
+// IsOpen reports whether the order may still trade.
+func (o Order) IsOpen() bool {
+	return o.State == Awaiting || o.State == Pending
 }
 
-func parseOrder(bo order) Order {
+// IsCancelled reports whether the limit order was stopped, expired or
+// cancelled by its time in force before being completely filled.
+func (o Order) IsCancelled() bool {
+	return o.State == Complete && o.LimitVolumeDecimal.Sign() > 0 &&
+		o.RemainingVolume().Sign() > 0
+}
+
+// FilledVolume returns the amount of the base currency traded so far.
+func (o Order) FilledVolume() Decimal {
+	return o.BaseDecimal
+}
+
+// RemainingVolume returns the part of the limit volume which hasn't been
+// filled yet. It is zero for market orders.
+func (o Order) RemainingVolume() Decimal {
+	r := o.LimitVolumeDecimal.Sub(o.BaseDecimal)
+	if r.Sign() < 0 {
+		return Decimal{}
+	}
+	return r
+}
+
+// AveragePrice returns the average price of the fills so far, rounded to 8
+// decimal places, or zero if nothing has been filled.
+func (o Order) AveragePrice() Decimal {
+	if o.BaseDecimal.IsZero() {
+		return Decimal{}
+	}
+	return o.CounterDecimal.Div(o.BaseDecimal, 8)
+}
+
+func parseOrder(d *decoder, bo order) Order {
 	var o Order
 	o.Id = bo.OrderId
 	o.Type = OrderType(bo.Type)
 	o.State = OrderState(bo.State)
-	o.CreatedAt = time.Unix(bo.CreationTimestamp/1000, 0)
-	o.LimitPrice = atofloat64(bo.LimitPrice)
-	o.LimitVolume = atofloat64(bo.LimitVolume)
-	o.Base = atofloat64(bo.Base)
-	o.Counter = atofloat64(bo.Counter)
-	o.FeeBase = atofloat64(bo.FeeBase)
-	o.FeeCounter = atofloat64(bo.FeeCounter)
+	o.CreatedAt = fromMillis(bo.CreationTimestamp)
+	o.LimitPriceDecimal = d.decimal("limit_price", bo.LimitPrice)
+	o.LimitVolumeDecimal = d.decimal("limit_volume", bo.LimitVolume)
+	o.BaseDecimal = d.decimal("base", bo.Base)
+	o.CounterDecimal = d.decimal("counter", bo.Counter)
+	o.FeeBaseDecimal = d.decimal("fee_base", bo.FeeBase)
+	o.FeeCounterDecimal = d.decimal("fee_counter", bo.FeeCounter)
+	o.LimitPrice = o.LimitPriceDecimal.Float64()
+	o.LimitVolume = o.LimitVolumeDecimal.Float64()
+	o.Base = o.BaseDecimal.Float64()
+	o.Counter = o.CounterDecimal.Float64()
+	o.FeeBase = o.FeeBaseDecimal.Float64()
+	o.FeeCounter = o.FeeCounterDecimal.Float64()
+	o.Pair = bo.Pair
+	o.CompletedAt = fromMillis(bo.CompletedTimestamp)
+	o.ExpiresAt = fromMillis(bo.ExpirationTimestamp)
+	o.StopPrice = d.optDecimal("stop_price", bo.StopPrice)
+	o.StopDirection = StopDirection(bo.StopDirection)
+	o.TimeInForce = TimeInForce(bo.TimeInForce)
+	o.ClientOrderID = bo.ClientOrderID
+	o.BaseAccountID = bo.BaseAccountID
+	o.CounterAccountID = bo.CounterAccountID
 	return o
 }
 
 // Returns a list of placed orders.
-// The list is truncated after 100 items.
+// The list is truncated after 100 items; use OrdersIter to list all orders.
 // If state is an empty string, the list won't be filtered by state.
 func (c *Client) ListOrders(pair string, state OrderState) ([]Order, error) {
+	return c.ListOrdersContext(context.Background(), pair, state)
+}
+
+// ListOrdersContext is like ListOrders but takes a context.
+func (c *Client) ListOrdersContext(ctx context.Context, pair string, state OrderState) ([]Order, error) {
 	params := url.Values{"pair": {pair}}
 	if state != "" {
 		params.Add("state", string(state))
 	}
 
 	var r orders
-	err := c.call("GET", "/api/1/listorders", params, &r)
+	err := c.call(ctx, "GET", "/api/1/listorders", params, &r)
 	if err != nil {
 		return nil, err
 	}
 	if r.Error != "" {
-		return nil, errors.New("BitX error: " + r.Error)
+		return nil, remoteError("/api/1/listorders", r.Error)
 	}
 
+	d := c.newDecoder("/api/1/listorders")
 	orders := make([]Order, len(r.Orders))
 	for i, bo := range r.Orders {
-		orders[i] = parseOrder(bo)
+		orders[i] = parseOrder(d, bo)
+	}
+	if d.err != nil {
+		return nil, d.err
 	}
 	return orders, nil
 }
@@ -354,18 +707,27 @@ func isValidPathID(id string) bool {
 
 // Get an order by its id.
 func (c *Client) GetOrder(id string) (*Order, error) {
+	return c.GetOrderContext(context.Background(), id)
+}
+
+// GetOrderContext is like GetOrder but takes a context.
+func (c *Client) GetOrderContext(ctx context.Context, id string) (*Order, error) {
 	if !isValidPathID(id) {
 		return nil, errors.New("invalid order id")
 	}
 	var bo order
-	err := c.call("GET", "/api/1/orders/"+id, nil, &bo)
+	err := c.call(ctx, "GET", "/api/1/orders/"+id, nil, &bo)
 	if err != nil {
 		return nil, err
 	}
 	if bo.Error != "" {
-		return nil, errors.New("BitX error: " + bo.Error)
+		return nil, remoteError("/api/1/orders/"+id, bo.Error)
+	}
+	d := c.newDecoder("/api/1/orders/" + id)
+	o := parseOrder(d, bo)
+	if d.err != nil {
+		return nil, d.err
 	}
-	o := parseOrder(bo)
 	return &o, nil
 }
 
@@ -376,15 +738,20 @@ type stoporder struct {
 
 // Request to stop an order.
 func (c *Client) StopOrder(id string) error {
+	return c.StopOrderContext(context.Background(), id)
+}
+
+// StopOrderContext is like StopOrder but takes a context.
+func (c *Client) StopOrderContext(ctx context.Context, id string) error {
 	form := make(url.Values)
 	form.Add("order_id", id)
 	var r stoporder
-	err := c.call("POST", "/api/1/stoporder", form, &r)
+	err := c.call(ctx, "POST", "/api/1/stoporder", form, &r)
 	if err != nil {
 		return err
 	}
 	if r.Error != "" {
-		return errors.New("BitX error: " + r.Error)
+		return remoteError("/api/1/stoporder", r.Error)
 	}
 	return nil
 }
@@ -408,17 +775,25 @@ type Balance struct {
 	Balance     float64
 	Reserved    float64
 	Unconfirmed float64
+
+	// Exact values of the fields above.
+	BalanceDecimal     Decimal
+	ReservedDecimal    Decimal
+	UnconfirmedDecimal Decimal
 }
 
-func parseBalances(bal []balance) []Balance {
+func parseBalances(d *decoder, bal []balance) []Balance {
 	var bl []Balance
 	for _, b := range bal {
 		var r Balance
 		r.AccountID = b.AccountID
 		r.Asset = b.Asset
-		r.Balance = atofloat64(b.Balance)
-		r.Reserved = atofloat64(b.Reserved)
-		r.Unconfirmed = atofloat64(b.Unconfirmed)
+		r.BalanceDecimal = d.decimal("balance", b.Balance)
+		r.ReservedDecimal = d.decimal("reserved", b.Reserved)
+		r.UnconfirmedDecimal = d.decimal("unconfirmed", b.Unconfirmed)
+		r.Balance = r.BalanceDecimal.Float64()
+		r.Reserved = r.ReservedDecimal.Float64()
+		r.Unconfirmed = r.UnconfirmedDecimal.Float64()
 		bl = append(bl, r)
 	}
 	return bl
@@ -426,33 +801,59 @@ func parseBalances(bal []balance) []Balance {
 
 // Returns the trading account balance and reserved funds.
 func (c *Client) Balance(asset string) (
+	balance float64, reserved float64, err error) {
+	return c.BalanceContext(context.Background(), asset)
+}
+
+// BalanceContext is like Balance but takes a context.
+func (c *Client) BalanceContext(ctx context.Context, asset string) (
 	balance float64, reserved float64, err error) {
 	var r balances
-	err = c.call("GET", "/api/1/balance", url.Values{"asset": {asset}}, &r)
+	err = c.call(ctx, "GET", "/api/1/balance", url.Values{"asset": {asset}}, &r)
 	if err != nil {
 		return 0, 0, err
 	}
 	if r.Error != "" {
-		return 0, 0, errors.New("BitX error: " + r.Error)
+		return 0, 0, remoteError("/api/1/balance", r.Error)
 	}
 	if len(r.Balance) == 0 {
 		return 0, 0, errors.New("Balance not returned")
 	}
-	bl := parseBalances(r.Balance)
+	d := c.newDecoder("/api/1/balance")
+	bl := parseBalances(d, r.Balance)
+	if d.err != nil {
+		return 0, 0, d.err
+	}
 	return bl[0].Balance, bl[0].Reserved, nil
 }
 
-// Balances return the balances of all accounts.
-func (c *Client) Balances() ([]Balance, error) {
+// Balances return the balances of all accounts, or only of the accounts for
+// the given assets.
+func (c *Client) Balances(assets ...string) ([]Balance, error) {
+	return c.BalancesContext(context.Background(), assets...)
+}
+
+// BalancesContext is like Balances but takes a context.
+func (c *Client) BalancesContext(ctx context.Context,
+	assets ...string) ([]Balance, error) {
+	params := make(url.Values)
+	for _, a := range assets {
+		params.Add("assets", a)
+	}
 	var r balances
-	err := c.call("GET", "/api/1/balance", nil, &r)
+	err := c.call(ctx, "GET", "/api/1/balance", params, &r)
 	if err != nil {
 		return nil, err
 	}
 	if r.Error != "" {
-		return nil, errors.New("BitX error: " + r.Error)
+		return nil, remoteError("/api/1/balance", r.Error)
+	}
+	d := c.newDecoder("/api/1/balance")
+	bl := parseBalances(d, r.Balance)
+	if d.err != nil {
+		return nil, d.err
 	}
-	return parseBalances(r.Balance), nil
+	return bl, nil
 }
 
 type sendResp struct {
@@ -461,6 +862,11 @@ type sendResp struct {
 }
 
 func (c *Client) Send(amount, currency, address, desc, message string) (string, error) {
+	return c.SendContext(context.Background(), amount, currency, address, desc, message)
+}
+
+// SendContext is like Send but takes a context.
+func (c *Client) SendContext(ctx context.Context, amount, currency, address, desc, message string) (string, error) {
 	form := make(url.Values)
 	form.Add("amount", amount)
 	form.Add("currency", currency)
@@ -469,7 +875,7 @@ func (c *Client) Send(amount, currency, address, desc, message string) (string,
 	form.Add("message", message)
 
 	var r sendResp
-	err := c.call("POST", "/api/1/send", form, &r)
+	err := c.call(ctx, "POST", "/api/1/send", form, &r)
 
 	return r.WithdrawalID, err
 }
@@ -487,17 +893,26 @@ type Address struct {
 	Address          string
 	TotalReceived    float64
 	TotalUnconfirmed float64
+
+	// Exact values of the fields above.
+	TotalReceivedDecimal    Decimal
+	TotalUnconfirmedDecimal Decimal
 }
 
-func parseAddress(a address) (Address, error) {
+func parseAddress(d *decoder, a address) (Address, error) {
 	if a.Error != "" {
-		return Address{}, errors.New("BitX error: " + a.Error)
+		return Address{}, remoteError(d.path, a.Error)
 	}
 	var r Address
 	r.Asset = a.Asset
 	r.Address = a.Address
-	r.TotalReceived = atofloat64(a.TotalReceived)
-	r.TotalUnconfirmed = atofloat64(a.TotalUnconfirmed)
+	r.TotalReceivedDecimal = d.decimal("total_received", a.TotalReceived)
+	r.TotalUnconfirmedDecimal = d.decimal("total_unconfirmed", a.TotalUnconfirmed)
+	r.TotalReceived = r.TotalReceivedDecimal.Float64()
+	r.TotalUnconfirmed = r.TotalUnconfirmedDecimal.Float64()
+	if d.err != nil {
+		return Address{}, d.err
+	}
 
 	return r, nil
 }
@@ -506,28 +921,44 @@ func parseAddress(a address) (Address, error) {
 // account and the amount received via the address, but can take optional
 // parameter to check non-default address
 func (c *Client) GetReceiveAddress(asset string, receiveAddress string) (Address, error) {
+	return c.GetReceiveAddressContext(context.Background(), asset, receiveAddress)
+}
+
+// GetReceiveAddressContext is like GetReceiveAddress but takes a context.
+func (c *Client) GetReceiveAddressContext(ctx context.Context, asset string, receiveAddress string) (Address, error) {
 	var a address
 	urlValues := url.Values{"asset": {asset}, "address": {receiveAddress}}
-	err := c.call("GET", "/api/1/funding_address", urlValues, &a)
+	err := c.call(ctx, "GET", "/api/1/funding_address", urlValues, &a)
 	if err != nil {
 		return Address{}, err
 	}
 
-	return parseAddress(a)
+	return parseAddress(c.newDecoder("/api/1/funding_address"), a)
 }
 
 // NewReceiveAddress allocates a new receive address to your account.
 // There is a rate limit of 1 address per hour, but bursts of up to 10
 // addresses are allowed.
 func (c *Client) NewReceiveAddress(asset string) (Address, error) {
+	return c.NewReceiveAddressContext(context.Background(), asset)
+}
+
+// NewReceiveAddressContext is like NewReceiveAddress but takes a context.
+func (c *Client) NewReceiveAddressContext(ctx context.Context, asset string) (Address, error) {
 	var a address
 	urlValues := url.Values{"asset": {asset}}
-	err := c.call("POST", "/api/1/funding_address", urlValues, &a)
+	err := c.call(ctx, "POST", "/api/1/funding_address", urlValues, &a)
 	if err != nil {
 		return Address{}, err
 	}
 
-	return parseAddress(a)
+	return parseAddress(c.newDecoder("/api/1/funding_address"), a)
+}
+
+type feeInfo struct {
+	ThirtyDayVolume string `json:"thirty_day_volume"`
+	MakerFee        string `json:"maker_fee"`
+	TakerFee        string `json:"taker_fee"`
 }
 
 // FeeInfo hold information about the user's fees and trading volume.
@@ -535,73 +966,177 @@ type FeeInfo struct {
 	ThirtyDayVolume float64 `json:"thirty_day_volume,string"`
 	MakerFee        float64 `json:"maker_fee,string"`
 	TakerFee        float64 `json:"taker_fee,string"`
+
+	// Exact values of the fields above.
+	ThirtyDayVolumeDecimal Decimal `json:"-"`
+	MakerFeeDecimal        Decimal `json:"-"`
+	TakerFeeDecimal        Decimal `json:"-"`
+}
+
+func parseFeeInfo(d *decoder, r feeInfo) FeeInfo {
+	var fi FeeInfo
+	fi.ThirtyDayVolumeDecimal = d.decimal("thirty_day_volume", r.ThirtyDayVolume)
+	fi.MakerFeeDecimal = d.decimal("maker_fee", r.MakerFee)
+	fi.TakerFeeDecimal = d.decimal("taker_fee", r.TakerFee)
+	fi.ThirtyDayVolume = fi.ThirtyDayVolumeDecimal.Float64()
+	fi.MakerFee = fi.MakerFeeDecimal.Float64()
+	fi.TakerFee = fi.TakerFeeDecimal.Float64()
+	return fi
 }
 
 // GetFeeInfo returns information about the user's fees and trading volume.
 func (c *Client) GetFeeInfo(pair string) (FeeInfo, error) {
-	var fi FeeInfo
+	return c.GetFeeInfoContext(context.Background(), pair)
+}
+
+// GetFeeInfoContext is like GetFeeInfo but takes a context.
+func (c *Client) GetFeeInfoContext(ctx context.Context, pair string) (FeeInfo, error) {
+	var fi feeInfo
 	urlValues := url.Values{"pair": {pair}}
-	err := c.call("GET", "/api/1/fee_info", urlValues, &fi)
+	err := c.call(ctx, "GET", "/api/1/fee_info", urlValues, &fi)
 	if err != nil {
 		return FeeInfo{}, err
 	}
 
-	return fi, nil
+	d := c.newDecoder("/api/1/fee_info")
+	r := parseFeeInfo(d, fi)
+	if d.err != nil {
+		return FeeInfo{}, d.err
+	}
+	return r, nil
+}
+
+type quoteResponse struct {
+	ID            int64  `json:"id,string"`
+	Type          string `json:"type"`
+	Pair          string `json:"pair"`
+	BaseAmount    string `json:"base_amount"`
+	CounterAmount string `json:"counter_amount"`
+	CreatedAt     int64  `json:"created_at"`
+	ExpiresAt     int64  `json:"expires_at"`
+	Discarded     bool   `json:"discarded"`
+	Exercised     bool   `json:"exercised"`
 }
 
 // QuoteResponse contains information about a specific quote
 type QuoteResponse struct {
-	ID            int64   `json:"id,string"`
-	Type          string  `json:"type"`
-	Pair          string  `json:"pair"`
-	BaseAmount    float64 `json:"base_amount,string"`
-	CounterAmount float64 `json:"counter_amount,string"`
-	CreatedAt     int64   `json:"created_at"`
-	ExpiresAt     int64   `json:"expires_at"`
-	Discarded     bool    `json:"discarded"`
-	Exercised     bool    `json:"exercised"`
+	ID            int64     `json:"id,string"`
+	Type          string    `json:"type"`
+	Pair          string    `json:"pair"`
+	BaseAmount    float64   `json:"base_amount,string"`
+	CounterAmount float64   `json:"counter_amount,string"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	Discarded     bool      `json:"discarded"`
+	Exercised     bool      `json:"exercised"`
+
+	// Exact values of the fields above.
+	BaseAmountDecimal    Decimal `json:"-"`
+	CounterAmountDecimal Decimal `json:"-"`
+}
+
+func parseQuote(d *decoder, r quoteResponse) QuoteResponse {
+	qr := QuoteResponse{
+		ID:                   r.ID,
+		Type:                 r.Type,
+		Pair:                 r.Pair,
+		BaseAmountDecimal:    d.decimal("base_amount", r.BaseAmount),
+		CounterAmountDecimal: d.decimal("counter_amount", r.CounterAmount),
+		CreatedAt:            fromMillis(r.CreatedAt),
+		ExpiresAt:            fromMillis(r.ExpiresAt),
+		Discarded:            r.Discarded,
+		Exercised:            r.Exercised,
+	}
+	qr.BaseAmount = qr.BaseAmountDecimal.Float64()
+	qr.CounterAmount = qr.CounterAmountDecimal.Float64()
+	return qr
 }
 
 // CreateQuote creates a quote of the given type (BUY or SELL) for the given
 // baseAmount of a specific pair (like XBTZAR)
 func (c *Client) CreateQuote(quoteType, baseAmount, pair string) (QuoteResponse, error) {
+	return c.CreateQuoteContext(context.Background(), quoteType, baseAmount, pair)
+}
+
+// CreateQuoteContext is like CreateQuote but takes a context.
+func (c *Client) CreateQuoteContext(ctx context.Context, quoteType, baseAmount, pair string) (QuoteResponse, error) {
 	if quoteType != "BUY" && quoteType != "SELL" {
 		return QuoteResponse{}, errors.New("quoteType must be either 'BUY' or 'SELL'")
 	}
-	var qr QuoteResponse
+	var qr quoteResponse
 	urlValues := url.Values{"type": {quoteType}, "base_amount": {baseAmount}, "pair": {pair}}
-	err := c.call("POST", "/api/1/quotes", urlValues, &qr)
+	err := c.call(ctx, "POST", "/api/1/quotes", urlValues, &qr)
 	if err != nil {
 		return QuoteResponse{}, err
 	}
 
-	return qr, nil
+	d := c.newDecoder("/api/1/quotes")
+	r := parseQuote(d, qr)
+	if d.err != nil {
+		return QuoteResponse{}, d.err
+	}
+	return r, nil
 }
 
-func (c *Client) quoteHandler(id, method string) (QuoteResponse, error) {
-	var qr QuoteResponse
-	err := c.call(method, "/api/1/quotes/"+id, nil, &qr)
+func (c *Client) quoteHandler(ctx context.Context, id, method string) (QuoteResponse, error) {
+	var qr quoteResponse
+	err := c.call(ctx, method, "/api/1/quotes/"+id, nil, &qr)
 
 	if err != nil {
 		return QuoteResponse{}, err
 	}
 
-	return qr, nil
+	d := c.newDecoder("/api/1/quotes/" + id)
+	r := parseQuote(d, qr)
+	if d.err != nil {
+		return QuoteResponse{}, d.err
+	}
+	return r, nil
 }
 
 // GetQuote returns the details of the specified quote
 func (c *Client) GetQuote(id string) (QuoteResponse, error) {
-	return c.quoteHandler(id, "GET")
+	return c.GetQuoteContext(context.Background(), id)
+}
+
+// GetQuoteContext is like GetQuote but takes a context.
+func (c *Client) GetQuoteContext(ctx context.Context, id string) (QuoteResponse, error) {
+	return c.quoteHandler(ctx, id, "GET")
 }
 
 // ExerciseQuote accepts the given quote
 func (c *Client) ExerciseQuote(id string) (QuoteResponse, error) {
-	return c.quoteHandler(id, "PUT")
+	return c.ExerciseQuoteContext(context.Background(), id)
+}
+
+// ExerciseQuoteContext is like ExerciseQuote but takes a context.
+func (c *Client) ExerciseQuoteContext(ctx context.Context, id string) (QuoteResponse, error) {
+	return c.quoteHandler(ctx, id, "PUT")
 }
 
 // DeleteQuote rejects a quote
 func (c *Client) DeleteQuote(id string) (QuoteResponse, error) {
-	return c.quoteHandler(id, "DELETE")
+	return c.DeleteQuoteContext(context.Background(), id)
+}
+
+// DeleteQuoteContext is like DeleteQuote but takes a context.
+func (c *Client) DeleteQuoteContext(ctx context.Context, id string) (QuoteResponse, error) {
+	return c.quoteHandler(ctx, id, "DELETE")
+}
+
+type orderTrade struct {
+	Base       string `json:"base"`
+	Counter    string `json:"counter"`
+	FeeBase    string `json:"fee_base"`
+	FeeCounter string `json:"fee_counter"`
+	IsBuy      bool   `json:"is_buy"`
+	OrderID    string `json:"order_id"`
+	Pair       string `json:"pair"`
+	Price      string `json:"price"`
+	Sequence   int64  `json:"sequence"`
+	Timestamp  int64  `json:"timestamp"`
+	Type       string `json:"type"`
+	Volume     string `json:"volume"`
 }
 
 type OrderTrade struct {
@@ -613,59 +1148,162 @@ type OrderTrade struct {
 	OrderID    string    `json:"order_id"`
 	Pair       string    `json:"pair"`
 	Price      float64   `json:"price,string"`
-	Timestamp  int64     `json:"timestamp"`
+	Sequence   int64     `json:"sequence"`
+	Timestamp  time.Time `json:"timestamp"`
 	Type       OrderType `json:"type"`
 	Volume     float64   `json:"volume,string"`
+
+	// Exact values of the fields above.
+	BaseDecimal       Decimal `json:"-"`
+	CounterDecimal    Decimal `json:"-"`
+	FeeBaseDecimal    Decimal `json:"-"`
+	FeeCounterDecimal Decimal `json:"-"`
+	PriceDecimal      Decimal `json:"-"`
+	VolumeDecimal     Decimal `json:"-"`
+}
+
+func parseOrderTrade(d *decoder, r orderTrade) OrderTrade {
+	t := OrderTrade{
+		BaseDecimal:       d.decimal("base", r.Base),
+		CounterDecimal:    d.decimal("counter", r.Counter),
+		FeeBaseDecimal:    d.decimal("fee_base", r.FeeBase),
+		FeeCounterDecimal: d.decimal("fee_counter", r.FeeCounter),
+		IsBuy:             r.IsBuy,
+		OrderID:           r.OrderID,
+		Pair:              r.Pair,
+		PriceDecimal:      d.decimal("price", r.Price),
+		Sequence:          r.Sequence,
+		Timestamp:         fromMillis(r.Timestamp),
+		Type:              OrderType(r.Type),
+		VolumeDecimal:     d.decimal("volume", r.Volume),
+	}
+	t.Base = t.BaseDecimal.Float64()
+	t.Counter = t.CounterDecimal.Float64()
+	t.FeeBase = t.FeeBaseDecimal.Float64()
+	t.FeeCounter = t.FeeCounterDecimal.Float64()
+	t.Price = t.PriceDecimal.Float64()
+	t.Volume = t.VolumeDecimal.Float64()
+	return t
 }
 
 type tradeResp struct {
-	Trades []OrderTrade `json:"trades"`
+	Trades []orderTrade `json:"trades"`
 }
 
 // ListTrades returns trades in your account for the given pair, sortest by
-// oldest first, since the given timestamp.
+// oldest first, since the given timestamp. Only one page of trades is
+// returned; use ListTradesIter to list all trades.
 func (c *Client) ListTrades(pair string, since int64) ([]OrderTrade, error) {
+	return c.ListTradesContext(context.Background(), pair, since)
+}
+
+// ListTradesContext is like ListTrades but takes a context.
+func (c *Client) ListTradesContext(ctx context.Context, pair string, since int64) ([]OrderTrade, error) {
 	params := url.Values{
 		"pair":  {pair},
 		"since": {strconv.FormatInt(since, 10)},
 	}
 	var resp tradeResp
-	err := c.call("GET", "/api/1/listtrades", params, &resp)
+	err := c.call(ctx, "GET", "/api/1/listtrades", params, &resp)
 	if err != nil {
 		return nil, err
 	}
-	return resp.Trades, nil
+	d := c.newDecoder("/api/1/listtrades")
+	trades := make([]OrderTrade, len(resp.Trades))
+	for i, t := range resp.Trades {
+		trades[i] = parseOrderTrade(d, t)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return trades, nil
+}
+
+type withdrawal struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	CreatedAt int64  `json:"created_at"`
+	Type      string `json:"type"`
+	Currency  string `json:"currency"`
+	Amount    string `json:"amount"`
+	Fee       string `json:"fee"`
 }
 
 type Withdrawal struct {
-	ID        string  `json:"id"`
-	Status    string  `json:"status"`
-	CreatedAt int64   `json:"created_at"`
-	Type      string  `json:"type"`
-	Currency  string  `json:"currency"`
-	Amount    float64 `json:"amount,string"`
-	Fee       float64 `json:"fee,string"`
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Type      string    `json:"type"`
+	Currency  string    `json:"currency"`
+	Amount    float64   `json:"amount,string"`
+	Fee       float64   `json:"fee,string"`
+
+	// Exact values of the fields above.
+	AmountDecimal Decimal `json:"-"`
+	FeeDecimal    Decimal `json:"-"`
+}
+
+func parseWithdrawal(d *decoder, r withdrawal) Withdrawal {
+	w := Withdrawal{
+		ID:            r.ID,
+		Status:        r.Status,
+		CreatedAt:     fromMillis(r.CreatedAt),
+		Type:          r.Type,
+		Currency:      r.Currency,
+		AmountDecimal: d.decimal("amount", r.Amount),
+		FeeDecimal:    d.decimal("fee", r.Fee),
+	}
+	w.Amount = w.AmountDecimal.Float64()
+	w.Fee = w.FeeDecimal.Float64()
+	return w
 }
 
 func (c *Client) GetWithdrawal(id string) (*Withdrawal, error) {
-	var w Withdrawal
-	err := c.call("GET", "/api/1/withdrawals/"+id, nil, &w)
+	return c.GetWithdrawalContext(context.Background(), id)
+}
+
+// GetWithdrawalContext is like GetWithdrawal but takes a context.
+func (c *Client) GetWithdrawalContext(ctx context.Context, id string) (*Withdrawal, error) {
+	var r withdrawal
+	err := c.call(ctx, "GET", "/api/1/withdrawals/"+id, nil, &r)
 	if err != nil {
 		return nil, err
 	}
+	d := c.newDecoder("/api/1/withdrawals/" + id)
+	w := parseWithdrawal(d, r)
+	if d.err != nil {
+		return nil, d.err
+	}
 	return &w, nil
 }
 
+type withdrawalList struct {
+	Withdrawals []withdrawal `json:"withdrawals"`
+}
+
 type WithdrawalList struct {
 	Withdrawals []Withdrawal `json:"withdrawals"`
 }
 
 func (c *Client) GetWithdrawals() (*WithdrawalList, error) {
-	var w WithdrawalList
-	err := c.call("GET", "/api/1/withdrawals", nil, &w)
+	return c.GetWithdrawalsContext(context.Background())
+}
+
+// GetWithdrawalsContext is like GetWithdrawals but takes a context.
+func (c *Client) GetWithdrawalsContext(ctx context.Context) (*WithdrawalList, error) {
+	var r withdrawalList
+	err := c.call(ctx, "GET", "/api/1/withdrawals", nil, &r)
 	if err != nil {
 		return nil, err
 	}
+	d := c.newDecoder("/api/1/withdrawals")
+	var w WithdrawalList
+	for _, bw := range r.Withdrawals {
+		w.Withdrawals = append(w.Withdrawals, parseWithdrawal(d, bw))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
 	return &w, nil
 }
 
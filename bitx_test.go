@@ -1,6 +1,15 @@
 package bitx
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestExample(t *testing.T) {
 	c := NewClient("test", "test")
@@ -8,3 +17,252 @@ func TestExample(t *testing.T) {
 		t.Errorf("Expected valid client, got: %v", c)
 	}
 }
+
+func newTestClient(t *testing.T, h http.HandlerFunc,
+	opts ...ClientOption) *Client {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts = append([]ClientOption{WithRetryPolicy(RetryPolicy{})}, opts...)
+	return NewClient("test", "test", append(opts, WithBaseURL(*u))...)
+}
+
+func TestContextCancelled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.TickerContext(ctx, "XBTZAR"); err == nil {
+		t.Errorf("Expected error for cancelled context")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"Insufficient balance.","error_code":"ErrInsufficientBalance"}`))
+	})
+	_, err := c.PostOrder("XBTZAR", BID, 1, 1, "", "")
+	if !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("Expected ErrInsufficientBalance, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest ||
+		apiErr.Path != "/api/1/postorder" ||
+		apiErr.Message != "Insufficient balance." {
+		t.Errorf("Unexpected error fields: %+v", apiErr)
+	}
+}
+
+func TestAPIErrorStatus(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	_, err := c.Ticker("XBTZAR")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("Did not expect ErrNotFound")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"timestamp":1,"bid":"1","ask":"2","last_trade":"1",` +
+			`"rolling_24_hour_volume":"3"}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	if _, err := c.Ticker("XBTZAR"); err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls)
+	}
+
+	calls = 0
+	if _, err := c.PostOrder("XBTZAR", BID, 1, 1, "", ""); err == nil {
+		t.Errorf("Expected error")
+	}
+	if calls != 1 {
+		t.Errorf("Expected POST not to be retried, got %d calls", calls)
+	}
+}
+
+func TestRetryAfterCapped(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Retry-After", "86400")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"timestamp":1,"bid":"1","ask":"2","last_trade":"1",` +
+			`"rolling_24_hour_volume":"3"}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 2,
+		MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}))
+
+	start := time.Now()
+	if _, err := c.Ticker("XBTZAR"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected Retry-After to be capped, waited %s", d)
+	}
+
+	h := http.Header{"Retry-After": {"99999999999999999"}}
+	if d, ok := retryAfter(h); !ok || d <= 0 {
+		t.Errorf("Expected huge Retry-After not to overflow, got %s", d)
+	}
+}
+
+func TestRetryNotOnDecodeError(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("<html>maintenance</html>"))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	if _, err := c.Ticker("XBTZAR"); err == nil {
+		t.Errorf("Expected error")
+	}
+	if calls != 1 {
+		t.Errorf("Expected decode error not to be retried, got %d calls", calls)
+	}
+}
+
+func TestBackoffWithoutCap(t *testing.T) {
+	p := RetryPolicy{MinBackoff: time.Second}
+	prev := time.Duration(0)
+	for attempt := 1; attempt <= 100; attempt++ {
+		d := p.backoff(attempt)
+		if d <= 0 {
+			t.Fatalf("Attempt %d: expected positive backoff, got %v", attempt, d)
+		}
+		if attempt > 1 && d < prev/2 {
+			t.Fatalf("Attempt %d: backoff decreased from %v to %v",
+				attempt, prev, d)
+		}
+		prev = d
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"asks":[{"price":"100.5","volume":""}],"bids":[]}`))
+	}
+
+	c := newTestClient(t, h)
+	_, _, err := c.OrderBook("XBTZAR")
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("Expected *ParseError, got %v", err)
+	}
+	if pe.Field != "volume" || pe.Value != "" || pe.Path != "/api/1/orderbook" {
+		t.Errorf("Unexpected error fields: %+v", pe)
+	}
+
+	c = newTestClient(t, h, WithLenientDecoding())
+	_, asks, err := c.OrderBook("XBTZAR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(asks) != 1 || asks[0].Price != 100.5 || asks[0].Volume != 0 {
+		t.Errorf("Unexpected asks: %+v", asks)
+	}
+}
+
+func TestTickerTimestamp(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"timestamp":1469606400123,"bid":"1","ask":"2",` +
+			`"last_trade":"1","rolling_24_hour_volume":"3"}`))
+	})
+	tk, err := c.Ticker("XBTZAR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms := tk.Timestamp.UnixNano() / int64(time.Millisecond); ms != 1469606400123 {
+		t.Errorf("Expected millisecond timestamp, got %d", ms)
+	}
+}
+
+func TestTickers(t *testing.T) {
+	var pairs []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pairs = r.URL.Query()["pair"]
+		w.Write([]byte(`{"tickers":[{"pair":"XBTZAR","timestamp":1469606400123,` +
+			`"bid":"1","ask":"2","last_trade":"1","rolling_24_hour_volume":"3",` +
+			`"status":"ACTIVE"},{"pair":"ETHZAR","timestamp":1469606400123,` +
+			`"bid":"4","ask":"5","last_trade":"4","rolling_24_hour_volume":"6",` +
+			`"status":"POST_ONLY"}]}`))
+	})
+	tickers, err := c.Tickers("XBTZAR", "ETHZAR")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pairs) != 2 {
+		t.Errorf("Expected 2 pair filters, got %v", pairs)
+	}
+	if len(tickers) != 2 {
+		t.Fatalf("Expected 2 tickers, got %d", len(tickers))
+	}
+	if tk := tickers["ETHZAR"]; tk.Pair != "ETHZAR" || tk.Bid != 4 ||
+		tk.Status != TradingPostOnly {
+		t.Errorf("Unexpected ticker: %+v", tk)
+	}
+}
+
+func TestPostOrderRoundsFloats(t *testing.T) {
+	var volume, price string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/markets") {
+			w.Write([]byte(testMarkets))
+			return
+		}
+		volume, price = r.FormValue("volume"), r.FormValue("price")
+		w.Write([]byte(`{"order_id":"BX1"}`))
+	}
+	// Computed at runtime so that the floats aren't exact.
+	a, b := 0.1, 0.2
+	v, p := a+b, 500000+a+b
+
+	c := newTestClient(t, h)
+	if _, err := c.PostOrder("XBTZAR", BID, v, p, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if volume != "0.30000000" || price != "500000.30000000" {
+		t.Errorf("Expected 8 decimal places, got volume %s price %s",
+			volume, price)
+	}
+
+	if _, err := c.PostOrder("XBTZAR", ASK, 0.123456789, p, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if volume != "0.12345678" {
+		t.Errorf("Expected truncated volume, got %s", volume)
+	}
+
+	c = newTestClient(t, h, WithMarketRounding())
+	if _, err := c.PostOrder("XBTZAR", BID, v, p, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if volume != "0.3000" || price != "500000" {
+		t.Errorf("Expected market scales, got volume %s price %s",
+			volume, price)
+	}
+}
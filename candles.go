@@ -0,0 +1,164 @@
+package bitx
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Candle is an OHLCV bar: the prices and total volume of the trades in the
+// interval starting at Timestamp.
+type Candle struct {
+	Timestamp              time.Time
+	Open, High, Low, Close Decimal
+	Volume                 Decimal
+}
+
+type candle struct {
+	Timestamp int64  `json:"timestamp"`
+	Open      string `json:"open"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Close     string `json:"close"`
+	Volume    string `json:"volume"`
+}
+
+type candles struct {
+	Error   string   `json:"error"`
+	Candles []candle `json:"candles"`
+}
+
+// Candles returns candles of the given duration for pair, oldest first,
+// starting at since. The API only supports certain durations, e.g. one
+// minute, five minutes, one hour and one day, and returns a limited number
+// of candles per request. Use a CandleBuilder for other durations.
+func (c *Client) Candles(pair string, since time.Time,
+	duration time.Duration) ([]Candle, error) {
+	return c.CandlesContext(context.Background(), pair, since, duration)
+}
+
+// CandlesContext is like Candles but takes a context.
+func (c *Client) CandlesContext(ctx context.Context, pair string,
+	since time.Time, duration time.Duration) ([]Candle, error) {
+	params := url.Values{
+		"pair":     {pair},
+		"since":    {strconv.FormatInt(since.UnixNano()/int64(time.Millisecond), 10)},
+		"duration": {strconv.FormatInt(int64(duration/time.Second), 10)},
+	}
+	var r candles
+	err := c.call(ctx, "GET", "/api/exchange/1/candles", params, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, remoteError("/api/exchange/1/candles", r.Error)
+	}
+
+	d := c.newDecoder("/api/exchange/1/candles")
+	res := make([]Candle, len(r.Candles))
+	for i, cd := range r.Candles {
+		res[i] = Candle{
+			Timestamp: fromMillis(cd.Timestamp),
+			Open:      d.decimal("open", cd.Open),
+			High:      d.decimal("high", cd.High),
+			Low:       d.decimal("low", cd.Low),
+			Close:     d.decimal("close", cd.Close),
+			Volume:    d.decimal("volume", cd.Volume),
+		}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return res, nil
+}
+
+// CandleBuilder aggregates trades into candles of any duration. Trades may
+// be added in any order. It is safe for concurrent use.
+type CandleBuilder struct {
+	duration time.Duration
+
+	mu      sync.Mutex
+	candles []candleState
+}
+
+type candleState struct {
+	Candle
+	first, last time.Time
+}
+
+// NewCandleBuilder returns a builder for candles of the given duration.
+// Candles start at multiples of duration since the zero time, so for example
+// hourly candles start on the hour.
+func NewCandleBuilder(duration time.Duration) *CandleBuilder {
+	return &CandleBuilder{duration: duration}
+}
+
+// Add adds a trade of volume at price.
+func (b *CandleBuilder) Add(ts time.Time, price, volume Decimal) {
+	start := ts.Truncate(b.duration)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	i := sort.Search(len(b.candles), func(i int) bool {
+		return !b.candles[i].Timestamp.Before(start)
+	})
+	if i == len(b.candles) || !b.candles[i].Timestamp.Equal(start) {
+		b.candles = append(b.candles, candleState{})
+		copy(b.candles[i+1:], b.candles[i:])
+		b.candles[i] = candleState{
+			Candle: Candle{
+				Timestamp: start,
+				Open:      price,
+				High:      price,
+				Low:       price,
+				Close:     price,
+				Volume:    volume,
+			},
+			first: ts,
+			last:  ts,
+		}
+		return
+	}
+
+	cs := &b.candles[i]
+	if ts.Before(cs.first) {
+		cs.Open = price
+		cs.first = ts
+	}
+	if !ts.Before(cs.last) {
+		cs.Close = price
+		cs.last = ts
+	}
+	if price.Cmp(cs.High) > 0 {
+		cs.High = price
+	}
+	if price.Cmp(cs.Low) < 0 {
+		cs.Low = price
+	}
+	cs.Volume = cs.Volume.Add(volume)
+}
+
+// AddTrade adds a public trade, as returned by Trades.
+func (b *CandleBuilder) AddTrade(t Trade) {
+	b.Add(t.Timestamp, t.PriceDecimal, t.VolumeDecimal)
+}
+
+// AddOrderTrade adds one of the user's trades, as returned by ListTrades.
+func (b *CandleBuilder) AddOrderTrade(t OrderTrade) {
+	b.Add(t.Timestamp, t.PriceDecimal, t.VolumeDecimal)
+}
+
+// Candles returns the candles built so far, oldest first. Intervals without
+// trades are omitted.
+func (b *CandleBuilder) Candles() []Candle {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := make([]Candle, len(b.candles))
+	for i, cs := range b.candles {
+		res[i] = cs.Candle
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package bitx
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCandles(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("since") != "1469606400123" || q.Get("duration") != "300" {
+			t.Errorf("Unexpected query: %v", q)
+		}
+		w.Write([]byte(`{"candles":[{"timestamp":1469606400000,"open":"1",` +
+			`"high":"3","low":"0.5","close":"2","volume":"10"}]}`))
+	})
+	candles, err := c.Candles("XBTZAR",
+		time.Unix(0, 1469606400123*int64(time.Millisecond)), 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 1 || candles[0].High.String() != "3" ||
+		candles[0].Volume.String() != "10" {
+		t.Errorf("Unexpected candles: %+v", candles)
+	}
+}
+
+func TestCandleBuilder(t *testing.T) {
+	b := NewCandleBuilder(time.Minute)
+	at := func(s int64) time.Time { return time.Unix(s, 0) }
+	// Newest first, as returned by Trades.
+	for _, tr := range []struct {
+		ts            int64
+		price, volume string
+	}{
+		{130, "7", "1"},
+		{119, "4", "1"},
+		{100, "9", "2"},
+		{61, "3", "0.5"},
+		{60, "5", "1"},
+	} {
+		b.AddTrade(Trade{Timestamp: at(tr.ts),
+			PriceDecimal:  MustParseDecimal(tr.price),
+			VolumeDecimal: MustParseDecimal(tr.volume)})
+	}
+
+	candles := b.Candles()
+	if len(candles) != 2 {
+		t.Fatalf("Expected 2 candles, got %d", len(candles))
+	}
+	c := candles[0]
+	if !c.Timestamp.Equal(at(60)) || c.Open.String() != "5" ||
+		c.High.String() != "9" || c.Low.String() != "3" ||
+		c.Close.String() != "4" || c.Volume.String() != "4.5" {
+		t.Errorf("Unexpected candle: %+v", c)
+	}
+	if c := candles[1]; !c.Timestamp.Equal(at(120)) || c.Open.String() != "7" {
+		t.Errorf("Unexpected candle: %+v", c)
+	}
+}
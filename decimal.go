@@ -0,0 +1,239 @@
+package bitx
+
+import (
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, stored as an arbitrary precision
+// integer scaled by a power of ten. The zero value is 0.
+//
+// Prices, volumes and amounts returned by the API are exposed as Decimal in
+// addition to float64 so that values like satoshi-level volumes are never
+// rounded.
+type Decimal struct {
+	i     *big.Int
+	scale int
+}
+
+// NewDecimal returns unscaled * 10^-scale.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	return Decimal{i: big.NewInt(unscaled), scale: scale}
+}
+
+// NewDecimalFromFloat64 returns the shortest decimal that converts back to
+// f exactly.
+func NewDecimalFromFloat64(f float64) Decimal {
+	d, err := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		// Only NaN and infinities can't be formatted as decimals.
+		return Decimal{}
+	}
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "-123.4500". The scale of the
+// result is the number of digits after the decimal point.
+func ParseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Decimal{}, errors.New("bitx: empty decimal")
+	}
+	digits := s
+	if digits[0] == '-' || digits[0] == '+' {
+		digits = digits[1:]
+	}
+	var scale int
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		scale = len(digits) - i - 1
+		digits = digits[:i] + digits[i+1:]
+	}
+	if digits == "" {
+		return Decimal{}, errors.New("bitx: invalid decimal " + strconv.Quote(s))
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return Decimal{}, errors.New("bitx: invalid decimal " + strconv.Quote(s))
+		}
+	}
+	i, _ := new(big.Int).SetString(digits, 10)
+	if s[0] == '-' {
+		i.Neg(i)
+	}
+	return Decimal{i: i, scale: scale}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s can't be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.i == nil {
+		return new(big.Int)
+	}
+	return d.i
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// quoRound returns x/y rounded half away from zero.
+func quoRound(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(x, y, new(big.Int))
+	r.Abs(r).Lsh(r, 1)
+	if r.CmpAbs(y) >= 0 {
+		if x.Sign() == y.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int {
+	return d.scale
+}
+
+// ToScale returns d with the given number of digits after the decimal point,
+// rounding half away from zero if digits are removed.
+func (d Decimal) ToScale(scale int) Decimal {
+	if scale >= d.scale {
+		return Decimal{
+			i:     new(big.Int).Mul(d.int(), pow10(scale-d.scale)),
+			scale: scale,
+		}
+	}
+	return Decimal{i: quoRound(d.int(), pow10(d.scale-scale)), scale: scale}
+}
+
+// Truncate returns d with at most scale digits after the decimal point,
+// rounding toward zero if digits are removed.
+func (d Decimal) Truncate(scale int) Decimal {
+	if scale >= d.scale {
+		return d
+	}
+	return Decimal{
+		i:     new(big.Int).Quo(d.int(), pow10(d.scale-scale)),
+		scale: scale,
+	}
+}
+
+// align returns the unscaled values of d and y at a common scale.
+func (d Decimal) align(y Decimal) (*big.Int, *big.Int, int) {
+	scale := d.scale
+	if y.scale > scale {
+		scale = y.scale
+	}
+	return d.ToScale(scale).i, y.ToScale(scale).i, scale
+}
+
+// Add returns d + y.
+func (d Decimal) Add(y Decimal) Decimal {
+	a, b, scale := d.align(y)
+	return Decimal{i: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - y.
+func (d Decimal) Sub(y Decimal) Decimal {
+	a, b, scale := d.align(y)
+	return Decimal{i: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d * y. The scale of the result is the sum of the scales.
+func (d Decimal) Mul(y Decimal) Decimal {
+	return Decimal{i: new(big.Int).Mul(d.int(), y.int()), scale: d.scale + y.scale}
+}
+
+// Div returns d / y rounded half away from zero to the given scale.
+// Div panics if y is zero.
+func (d Decimal) Div(y Decimal, scale int) Decimal {
+	num := new(big.Int).Set(d.int())
+	den := new(big.Int).Set(y.int())
+	if e := scale - d.scale + y.scale; e >= 0 {
+		num.Mul(num, pow10(e))
+	} else {
+		den.Mul(den, pow10(-e))
+	}
+	return Decimal{i: quoRound(num, den), scale: scale}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{i: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{i: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of d.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1 if d < y, 0 if d == y and 1 if d > y.
+func (d Decimal) Cmp(y Decimal) int {
+	a, b, _ := d.align(y)
+	return a.Cmp(b)
+}
+
+// String formats d with exactly Scale() digits after the decimal point.
+func (d Decimal) String() string {
+	s := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if len(s) <= d.scale {
+			s = strings.Repeat("0", d.scale-len(s)+1) + s
+		}
+		s = s[:len(s)-d.scale] + "." + s[len(s)-d.scale:]
+	} else if d.scale < 0 {
+		s += strings.Repeat("0", -d.scale)
+	}
+	if d.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// Float64 returns the float64 nearest to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// MarshalJSON encodes d as a JSON string, which is how the API represents
+// decimal values.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalJSON decodes d from either a JSON string or a JSON number.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
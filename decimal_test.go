@@ -0,0 +1,94 @@
+package bitx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+		err bool
+	}{
+		{in: "0", out: "0"},
+		{in: "1.5", out: "1.5"},
+		{in: "-0.00000001", out: "-0.00000001"},
+		{in: "+12.340", out: "12.340"},
+		{in: ".5", out: "0.5"},
+		{in: "", err: true},
+		{in: "-", err: true},
+		{in: "1.2.3", err: true},
+		{in: "1e8", err: true},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.in)
+		if test.err {
+			if err == nil {
+				t.Errorf("ParseDecimal(%q): expected error", test.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", test.in, err)
+			continue
+		}
+		if d.String() != test.out {
+			t.Errorf("ParseDecimal(%q) = %s, expected %s", test.in, d, test.out)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a := MustParseDecimal("1.23456789")
+	b := MustParseDecimal("0.1")
+
+	if s := a.Add(b).String(); s != "1.33456789" {
+		t.Errorf("Add: got %s", s)
+	}
+	if s := b.Sub(a).String(); s != "-1.13456789" {
+		t.Errorf("Sub: got %s", s)
+	}
+	if s := a.Mul(b).String(); s != "0.123456789" {
+		t.Errorf("Mul: got %s", s)
+	}
+	if s := MustParseDecimal("2").Div(MustParseDecimal("3"), 4).String(); s != "0.6667" {
+		t.Errorf("Div: got %s", s)
+	}
+	if s := MustParseDecimal("-0.125").ToScale(2).String(); s != "-0.13" {
+		t.Errorf("ToScale: got %s", s)
+	}
+	if s := MustParseDecimal("-0.129").Truncate(2).String(); s != "-0.12" {
+		t.Errorf("Truncate: got %s", s)
+	}
+	if a.Cmp(b) != 1 || b.Cmp(a) != -1 || b.Cmp(MustParseDecimal("0.10")) != 0 {
+		t.Errorf("Cmp: unexpected result")
+	}
+	var zero Decimal
+	if !zero.IsZero() || zero.String() != "0" || zero.Add(b).String() != "0.1" {
+		t.Errorf("Zero value: unexpected result")
+	}
+}
+
+func TestNewDecimalFromFloat64(t *testing.T) {
+	if s := NewDecimalFromFloat64(0.00000123).String(); s != "0.00000123" {
+		t.Errorf("Expected 0.00000123, got %s", s)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	var v struct {
+		A Decimal `json:"a"`
+		B Decimal `json:"b"`
+	}
+	if err := json.Unmarshal([]byte(`{"a":"0.00012345","b":42.5}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"a":"0.00012345","b":"42.5"}` {
+		t.Errorf("Unexpected JSON: %s", b)
+	}
+}
@@ -0,0 +1,50 @@
+package bitx
+
+import "time"
+
+// decoder converts the string fields of an API response into typed values.
+// It records the first field that fails to parse, unless the client was
+// created with WithLenientDecoding, in which case such fields become zero.
+type decoder struct {
+	path    string
+	lenient bool
+	err     error
+}
+
+func (c *Client) newDecoder(path string) *decoder {
+	return &decoder{path: path, lenient: c.lenientDecoding}
+}
+
+func (d *decoder) fail(field, value string, err error) {
+	if d.lenient || d.err != nil {
+		return
+	}
+	d.err = &ParseError{Path: d.path, Field: field, Value: value, Err: err}
+}
+
+// decimal parses a required decimal field.
+func (d *decoder) decimal(field, s string) Decimal {
+	v, err := ParseDecimal(s)
+	if err != nil {
+		d.fail(field, s, err)
+		return Decimal{}
+	}
+	return v
+}
+
+// fromMillis converts a Unix timestamp in milliseconds, as used throughout
+// the API, to a time.Time. A zero timestamp becomes the zero time.
+func fromMillis(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// optDecimal parses an optional decimal field, which is zero if empty.
+func (d *decoder) optDecimal(field, s string) Decimal {
+	if s == "" {
+		return Decimal{}
+	}
+	return d.decimal(field, s)
+}
@@ -0,0 +1,106 @@
+package bitx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when the API rejects a request or reports an error
+// in its response. Use errors.Is to compare it against the sentinel errors
+// below, or errors.As to inspect the details.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the value of the error_code field, e.g.
+	// "ErrInsufficientBalance". It may be empty.
+	Code string
+
+	// Message is the human readable error message.
+	Message string
+
+	// Path is the API path of the request, e.g. "/api/1/postorder".
+	Path string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("bitx: %s: remote error %d %s: %s",
+			e.Path, e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("bitx: %s: remote error %d: %s",
+		e.Path, e.StatusCode, e.Message)
+}
+
+// Is reports whether e matches one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		if e.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+	case ErrNotFound:
+		if e.StatusCode == http.StatusNotFound {
+			return true
+		}
+	}
+	for _, code := range errorCodes[target] {
+		if e.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrInsufficientBalance = errors.New("bitx: insufficient balance")
+	ErrRateLimited         = errors.New("bitx: rate limited")
+	ErrInvalidPair         = errors.New("bitx: invalid pair")
+	ErrNotFound            = errors.New("bitx: not found")
+
+	// ErrDuplicateClientOrderID is returned when an order is placed with a
+	// client order id which has already been used.
+	ErrDuplicateClientOrderID = errors.New("bitx: duplicate client order id")
+)
+
+// errorCodes maps sentinel errors to the error_code values that match them.
+var errorCodes = map[error][]string{
+	ErrInsufficientBalance: {"ErrInsufficientBalance", "ErrInsufficientFunds"},
+	ErrRateLimited:         {"ErrTooManyRequests", "ErrRateLimitExceeded"},
+	ErrInvalidPair:         {"ErrInvalidMarketPair", "ErrInvalidPair", "ErrMarketUnavailable"},
+	ErrNotFound: {"ErrNotFound", "ErrOrderNotFound", "ErrAccountNotFound",
+		"ErrWithdrawalNotFound", "ErrQuoteNotFound"},
+	ErrDuplicateClientOrderID: {"ErrDuplicateClientOrderID"},
+}
+
+// remoteError returns an error for a successful response which nevertheless
+// contains an error message.
+func remoteError(path, msg string) error {
+	return &APIError{StatusCode: http.StatusOK, Message: msg, Path: path}
+}
+
+// ParseError is returned when a field in an API response can't be parsed,
+// for example a price which is empty or isn't a number. Clients created with
+// WithLenientDecoding never return a ParseError.
+type ParseError struct {
+	// Path is the API path of the request.
+	Path string
+
+	// Field is the name of the JSON field, e.g. "limit_price".
+	Field string
+
+	// Value is the raw value of the field.
+	Value string
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("bitx: %s: invalid value %q for field %s: %v",
+		e.Path, e.Value, e.Field, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,235 @@
+package bitx
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// listTradesPageSize is the maximum number of trades the API returns per
+// page.
+const listTradesPageSize = 1000
+
+// ListTradesQuery selects the trades returned by ListTradesIter. All fields
+// are optional.
+type ListTradesQuery struct {
+	// Pair limits the trades to one market. Trades for all markets are
+	// returned if it is empty.
+	Pair string
+
+	// Only trades made at or after Since and before Before are returned.
+	Since  time.Time
+	Before time.Time
+
+	// AfterSeq and BeforeSeq limit the trades to sequence numbers at or
+	// after AfterSeq and before BeforeSeq. Sequence numbers are per market,
+	// so they should only be used with Pair.
+	AfterSeq  int64
+	BeforeSeq int64
+
+	// OrderID limits the trades to those of one order.
+	OrderID string
+
+	// Cursor resumes iteration after the last trade returned by an earlier
+	// iterator with the same query. It takes precedence over Since.
+	Cursor *TradesCursor
+
+	// Limit is the maximum number of trades to return. Zero means no limit.
+	Limit int
+}
+
+// TradesCursor is the position of an OrderTradeIterator. It can be persisted,
+// e.g. as JSON, and passed in ListTradesQuery.Cursor to resume iteration.
+type TradesCursor struct {
+	// Timestamp is the time of the last trade returned.
+	Timestamp time.Time `json:"timestamp"`
+
+	// Sequences holds the highest sequence number of the trades returned at
+	// Timestamp, by pair.
+	Sequences map[string]int64 `json:"sequences"`
+}
+
+// returned reports whether t is at or before the cursor.
+func (c *TradesCursor) returned(t OrderTrade) bool {
+	if t.Timestamp.Equal(c.Timestamp) {
+		seq, ok := c.Sequences[t.Pair]
+		return ok && t.Sequence <= seq
+	}
+	return t.Timestamp.Before(c.Timestamp)
+}
+
+// OrderTradeIterator walks through your trades from oldest to newest,
+// fetching pages as needed. Use it like this:
+//
+//	it := c.ListTradesIter(bitx.ListTradesQuery{Pair: "XBTZAR"})
+//	for it.Next() {
+//		t := it.Trade()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//	cursor := it.Cursor()
+type OrderTradeIterator struct {
+	c   *Client
+	ctx context.Context
+	q   ListTradesQuery
+
+	cursor TradesCursor
+	page   []OrderTrade
+	trade  OrderTrade
+	n      int
+	last   bool
+	err    error
+}
+
+// ListTradesIter returns an iterator over all your trades matching q, unlike
+// ListTrades which only returns one page.
+func (c *Client) ListTradesIter(q ListTradesQuery) *OrderTradeIterator {
+	return c.ListTradesIterContext(context.Background(), q)
+}
+
+// ListTradesIterContext is like ListTradesIter but takes a context which is
+// used for all requests made by the iterator.
+func (c *Client) ListTradesIterContext(ctx context.Context,
+	q ListTradesQuery) *OrderTradeIterator {
+	it := &OrderTradeIterator{c: c, ctx: ctx, q: q}
+	if q.Cursor != nil {
+		it.cursor.Timestamp = q.Cursor.Timestamp
+		it.cursor.Sequences = make(map[string]int64)
+		for p, seq := range q.Cursor.Sequences {
+			it.cursor.Sequences[p] = seq
+		}
+	} else {
+		it.cursor.Timestamp = q.Since
+	}
+	return it
+}
+
+// Next advances to the next trade. It returns false when there are no more
+// trades or an error occurred.
+func (it *OrderTradeIterator) Next() bool {
+	if it.err != nil || (it.q.Limit > 0 && it.n >= it.q.Limit) {
+		return false
+	}
+	for {
+		for len(it.page) == 0 {
+			if it.last {
+				return false
+			}
+			if err := it.fetch(); err != nil {
+				it.err = err
+				return false
+			}
+		}
+
+		it.trade = it.page[0]
+		it.page = it.page[1:]
+		it.advance(it.trade)
+		if it.q.OrderID == "" || it.trade.OrderID == it.q.OrderID {
+			it.n++
+			return true
+		}
+	}
+}
+
+// Trade returns the current trade.
+func (it *OrderTradeIterator) Trade() OrderTrade {
+	return it.trade
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *OrderTradeIterator) Err() error {
+	return it.err
+}
+
+// Cursor returns the position after the current trade. Pass it in
+// ListTradesQuery.Cursor to continue from there later.
+func (it *OrderTradeIterator) Cursor() TradesCursor {
+	c := TradesCursor{
+		Timestamp: it.cursor.Timestamp,
+		Sequences: make(map[string]int64, len(it.cursor.Sequences)),
+	}
+	for p, seq := range it.cursor.Sequences {
+		c.Sequences[p] = seq
+	}
+	return c
+}
+
+// advance moves the cursor past t.
+func (it *OrderTradeIterator) advance(t OrderTrade) {
+	if !t.Timestamp.Equal(it.cursor.Timestamp) {
+		it.cursor.Timestamp = t.Timestamp
+		it.cursor.Sequences = make(map[string]int64)
+	}
+	if it.cursor.Sequences == nil {
+		it.cursor.Sequences = make(map[string]int64)
+	}
+	if t.Sequence > it.cursor.Sequences[t.Pair] {
+		it.cursor.Sequences[t.Pair] = t.Sequence
+	}
+}
+
+func (it *OrderTradeIterator) fetch() error {
+	params := url.Values{"limit": {strconv.Itoa(listTradesPageSize)}}
+	if it.q.Pair != "" {
+		params.Set("pair", it.q.Pair)
+	}
+	if !it.cursor.Timestamp.IsZero() {
+		// Trades are only ordered to the millisecond, so include the
+		// millisecond of the cursor and skip the trades which were already
+		// returned.
+		ms := it.cursor.Timestamp.UnixNano() / int64(time.Millisecond)
+		params.Set("since", strconv.FormatInt(ms, 10))
+	}
+	if !it.q.Before.IsZero() {
+		ms := it.q.Before.UnixNano() / int64(time.Millisecond)
+		params.Set("before", strconv.FormatInt(ms, 10))
+	}
+	if it.q.AfterSeq > 0 {
+		params.Set("after_seq", strconv.FormatInt(it.q.AfterSeq, 10))
+	}
+	if it.q.BeforeSeq > 0 {
+		params.Set("before_seq", strconv.FormatInt(it.q.BeforeSeq, 10))
+	}
+
+	var r tradeResp
+	err := it.c.call(it.ctx, "GET", "/api/1/listtrades", params, &r)
+	if err != nil {
+		return err
+	}
+	if len(r.Trades) < listTradesPageSize {
+		it.last = true
+	}
+
+	d := it.c.newDecoder("/api/1/listtrades")
+	var page []OrderTrade
+	for _, rt := range r.Trades {
+		t := parseOrderTrade(d, rt)
+		if !it.cursor.returned(t) {
+			page = append(page, t)
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(page) == 0 {
+		// No progress is possible.
+		it.last = true
+		return nil
+	}
+
+	sort.SliceStable(page, func(i, j int) bool {
+		if !page[i].Timestamp.Equal(page[j].Timestamp) {
+			return page[i].Timestamp.Before(page[j].Timestamp)
+		}
+		if page[i].Pair != page[j].Pair {
+			return page[i].Pair < page[j].Pair
+		}
+		return page[i].Sequence < page[j].Sequence
+	})
+	it.page = page
+	return nil
+}
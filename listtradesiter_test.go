@@ -0,0 +1,73 @@
+package bitx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListTradesIter(t *testing.T) {
+	// 2500 trades across two pairs, with several trades per millisecond so
+	// that page boundaries fall within a millisecond.
+	const total = 2500
+	ts := func(i int) int64 { return int64(10000 + i/5) }
+	pair := func(i int) string { return []string{"XBTZAR", "ETHZAR"}[i%2] }
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		since, _ := strconv.ParseInt(q.Get("since"), 10, 64)
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		var entries []string
+		for i := 0; i < total && len(entries) < limit; i++ {
+			if ts(i) < since || (q.Get("pair") != "" && q.Get("pair") != pair(i)) {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf(`{"pair":%q,"sequence":%d,`+
+				`"timestamp":%d,"order_id":"BX%d","base":"1","counter":"1",`+
+				`"fee_base":"0","fee_counter":"0","price":"1","volume":"1",`+
+				`"type":"BID"}`, pair(i), i/2+1, ts(i), i%3))
+		}
+		fmt.Fprintf(w, `{"trades":[%s]}`, strings.Join(entries, ","))
+	})
+
+	seen := make(map[string]bool)
+	count := func(it *OrderTradeIterator) int {
+		var n int
+		for it.Next() {
+			tr := it.Trade()
+			key := tr.Pair + strconv.FormatInt(tr.Sequence, 10)
+			if seen[key] {
+				t.Errorf("Duplicate trade %s", key)
+			}
+			seen[key] = true
+			n++
+		}
+		if err := it.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	it := c.ListTradesIter(ListTradesQuery{Limit: 1203})
+	if n := count(it); n != 1203 {
+		t.Errorf("Expected 1203 trades, got %d", n)
+	}
+	cursor := it.Cursor()
+	if n := count(c.ListTradesIter(ListTradesQuery{Cursor: &cursor})); n != total-1203 {
+		t.Errorf("Expected %d trades after cursor, got %d", total-1203, n)
+	}
+
+	it = c.ListTradesIter(ListTradesQuery{Pair: "XBTZAR", OrderID: "BX0"})
+	var n int
+	for it.Next() {
+		if tr := it.Trade(); tr.OrderID != "BX0" || tr.Pair != "XBTZAR" {
+			t.Errorf("Unexpected trade %+v", tr)
+		}
+		n++
+	}
+	if n != 417 {
+		t.Errorf("Expected 417 trades, got %d", n)
+	}
+}
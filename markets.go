@@ -0,0 +1,263 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// marketsTTL is how long market rules are cached for order validation.
+const marketsTTL = 10 * time.Minute
+
+// TradingStatus is whether orders can be placed in a market.
+type TradingStatus string
+
+const (
+	TradingActive = TradingStatus("ACTIVE")
+
+	// TradingPostOnly markets only accept post-only limit orders.
+	TradingPostOnly = TradingStatus("POST_ONLY")
+
+	TradingSuspended = TradingStatus("SUSPENDED")
+)
+
+// MarketInfo describes the trading rules of a market.
+type MarketInfo struct {
+	Pair            string
+	BaseCurrency    string
+	CounterCurrency string
+	TradingStatus   TradingStatus
+
+	// Orders must have a volume and price within these limits.
+	MinVolume, MaxVolume Decimal
+	MinPrice, MaxPrice   Decimal
+
+	// VolumeScale and PriceScale are the maximum number of digits after the
+	// decimal point of order volumes and prices.
+	VolumeScale int
+	PriceScale  int
+	FeeScale    int
+}
+
+type market struct {
+	MarketID        string `json:"market_id"`
+	TradingStatus   string `json:"trading_status"`
+	BaseCurrency    string `json:"base_currency"`
+	CounterCurrency string `json:"counter_currency"`
+	MinVolume       string `json:"min_volume"`
+	MaxVolume       string `json:"max_volume"`
+	VolumeScale     int    `json:"volume_scale"`
+	MinPrice        string `json:"min_price"`
+	MaxPrice        string `json:"max_price"`
+	PriceScale      int    `json:"price_scale"`
+	FeeScale        int    `json:"fee_scale"`
+}
+
+type marketsResponse struct {
+	Markets []market `json:"markets"`
+}
+
+func parseMarket(d *decoder, m market) MarketInfo {
+	return MarketInfo{
+		Pair:            m.MarketID,
+		BaseCurrency:    m.BaseCurrency,
+		CounterCurrency: m.CounterCurrency,
+		TradingStatus:   TradingStatus(m.TradingStatus),
+		MinVolume:       d.decimal("min_volume", m.MinVolume),
+		MaxVolume:       d.decimal("max_volume", m.MaxVolume),
+		MinPrice:        d.decimal("min_price", m.MinPrice),
+		MaxPrice:        d.decimal("max_price", m.MaxPrice),
+		VolumeScale:     m.VolumeScale,
+		PriceScale:      m.PriceScale,
+		FeeScale:        m.FeeScale,
+	}
+}
+
+// Markets returns the trading rules of the given pairs, or of all markets if
+// no pairs are given.
+func (c *Client) Markets(pairs ...string) ([]MarketInfo, error) {
+	return c.MarketsContext(context.Background(), pairs...)
+}
+
+// MarketsContext is like Markets but takes a context.
+func (c *Client) MarketsContext(ctx context.Context,
+	pairs ...string) ([]MarketInfo, error) {
+	params := make(url.Values)
+	for _, p := range pairs {
+		params.Add("pair", p)
+	}
+	var r marketsResponse
+	err := c.call(ctx, "GET", "/api/exchange/1/markets", params, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := c.newDecoder("/api/exchange/1/markets")
+	markets := make([]MarketInfo, 0, len(r.Markets))
+	for _, m := range r.Markets {
+		markets = append(markets, parseMarket(d, m))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return markets, nil
+}
+
+// RoundPrice rounds p half away from zero to the market's price scale.
+func (m MarketInfo) RoundPrice(p Decimal) Decimal {
+	return p.ToScale(m.PriceScale)
+}
+
+// RoundVolume rounds v toward zero to the market's volume scale, so that the
+// rounded volume never exceeds v.
+func (m MarketInfo) RoundVolume(v Decimal) Decimal {
+	return v.Truncate(m.VolumeScale)
+}
+
+// RoundLimitOrder returns o with its volume, price and stop price rounded
+// to the market's scales using RoundVolume and RoundPrice.
+func (m MarketInfo) RoundLimitOrder(o LimitOrder) LimitOrder {
+	o.Volume = m.RoundVolume(o.Volume)
+	o.Price = m.RoundPrice(o.Price)
+	if !o.StopPrice.IsZero() {
+		o.StopPrice = m.RoundPrice(o.StopPrice)
+	}
+	return o
+}
+
+// ValidateLimitOrder returns an error if the market would reject o because
+// of its trading status, limits or scales. It doesn't call o.Validate.
+func (m MarketInfo) ValidateLimitOrder(o LimitOrder) error {
+	if o.Pair != m.Pair {
+		return errors.New("bitx: order pair " + o.Pair +
+			" doesn't match market " + m.Pair)
+	}
+	switch m.TradingStatus {
+	case TradingActive:
+	case TradingPostOnly:
+		if !o.PostOnly {
+			return errors.New("bitx: market " + m.Pair +
+				" only accepts post-only orders")
+		}
+	default:
+		return errors.New("bitx: market " + m.Pair + " is " +
+			string(m.TradingStatus))
+	}
+
+	if err := checkRange("volume", o.Volume, m.MinVolume, m.MaxVolume,
+		m.VolumeScale); err != nil {
+		return err
+	}
+	if err := checkRange("price", o.Price, m.MinPrice, m.MaxPrice,
+		m.PriceScale); err != nil {
+		return err
+	}
+	if !o.StopPrice.IsZero() &&
+		o.StopPrice.Truncate(m.PriceScale).Cmp(o.StopPrice) != 0 {
+		return errors.New("bitx: stop price has more than " +
+			strconv.Itoa(m.PriceScale) + " decimal places")
+	}
+	return nil
+}
+
+func checkRange(field string, v, min, max Decimal, scale int) error {
+	if v.Cmp(min) < 0 {
+		return errors.New("bitx: " + field + " " + v.String() +
+			" is below the minimum of " + min.String())
+	}
+	if max.Sign() > 0 && v.Cmp(max) > 0 {
+		return errors.New("bitx: " + field + " " + v.String() +
+			" is above the maximum of " + max.String())
+	}
+	if v.Truncate(scale).Cmp(v) != 0 {
+		return errors.New("bitx: " + field + " has more than " +
+			strconv.Itoa(scale) + " decimal places")
+	}
+	return nil
+}
+
+// market returns the trading rules of pair, fetching all markets if they
+// haven't been fetched recently. It returns ErrInvalidPair if there is no
+// such market. Only one fetch is made at a
+// time; other callers wait for it without holding marketsMu.
+func (c *Client) market(ctx context.Context, pair string) (MarketInfo, error) {
+	for {
+		c.marketsMu.Lock()
+		if time.Since(c.marketsFetched) < marketsTTL {
+			m, ok := c.markets[pair]
+			c.marketsMu.Unlock()
+			if !ok {
+				return MarketInfo{}, ErrInvalidPair
+			}
+			return m, nil
+		}
+		fetching, fetched := c.marketsFetching, c.marketsFetched
+		if fetching == nil {
+			break
+		}
+		c.marketsMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return MarketInfo{}, ctx.Err()
+		case <-fetching:
+		}
+
+		c.marketsMu.Lock()
+		m, ok := c.markets[pair]
+		refreshed := !c.marketsFetched.Equal(fetched)
+		c.marketsMu.Unlock()
+		if refreshed {
+			if !ok {
+				return MarketInfo{}, ErrInvalidPair
+			}
+			return m, nil
+		}
+		// The fetch failed, so try again with our own context.
+	}
+
+	done := make(chan struct{})
+	c.marketsFetching = done
+	c.marketsMu.Unlock()
+
+	markets, err := c.MarketsContext(ctx)
+
+	c.marketsMu.Lock()
+	c.marketsFetching = nil
+	close(done)
+	if err == nil {
+		c.marketsFetched = time.Now()
+		c.markets = make(map[string]MarketInfo, len(markets))
+		for _, m := range markets {
+			c.markets[m.Pair] = m
+		}
+	}
+	m, ok := c.markets[pair]
+	c.marketsMu.Unlock()
+	if err != nil {
+		return MarketInfo{}, err
+	}
+	if !ok {
+		return MarketInfo{}, ErrInvalidPair
+	}
+	return m, nil
+}
+
+// applyMarketRules rounds and validates o according to the options the
+// client was created with.
+func (c *Client) applyMarketRules(ctx context.Context,
+	o LimitOrder) (LimitOrder, error) {
+	if !c.validateMarkets && !c.roundToMarkets {
+		return o, nil
+	}
+	m, err := c.market(ctx, o.Pair)
+	if err != nil {
+		return o, err
+	}
+	if c.roundToMarkets {
+		o = m.RoundLimitOrder(o)
+	}
+	return o, m.ValidateLimitOrder(o)
+}
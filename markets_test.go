@@ -0,0 +1,154 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testMarkets = `{"markets":[{"market_id":"XBTZAR",` +
+	`"trading_status":"ACTIVE","base_currency":"XBT",` +
+	`"counter_currency":"ZAR","min_volume":"0.0005",` +
+	`"max_volume":"100","volume_scale":4,"min_price":"100",` +
+	`"max_price":"10000000","price_scale":0,"fee_scale":8},` +
+	`{"market_id":"ETHZAR","trading_status":"POST_ONLY",` +
+	`"base_currency":"ETH","counter_currency":"ZAR",` +
+	`"min_volume":"0.01","max_volume":"1000","volume_scale":2,` +
+	`"min_price":"10","max_price":"1000000","price_scale":0,` +
+	`"fee_scale":8}]}`
+
+func TestMarkets(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testMarkets))
+	})
+	markets, err := c.Markets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("Expected 2 markets, got %d", len(markets))
+	}
+	m := markets[0]
+	if m.Pair != "XBTZAR" || m.VolumeScale != 4 ||
+		m.MinVolume.String() != "0.0005" || m.TradingStatus != TradingActive {
+		t.Errorf("Unexpected market: %+v", m)
+	}
+
+	valid := LimitOrder{Pair: "XBTZAR", Type: BID,
+		Volume: MustParseDecimal("0.001"), Price: MustParseDecimal("500000")}
+	for _, test := range []struct {
+		name string
+		m    MarketInfo
+		o    func(LimitOrder) LimitOrder
+		ok   bool
+	}{
+		{"valid", m, func(o LimitOrder) LimitOrder { return o }, true},
+		{"below min volume", m, func(o LimitOrder) LimitOrder {
+			o.Volume = MustParseDecimal("0.0001")
+			return o
+		}, false},
+		{"volume scale", m, func(o LimitOrder) LimitOrder {
+			o.Volume = MustParseDecimal("0.00101")
+			return o
+		}, false},
+		{"price scale", m, func(o LimitOrder) LimitOrder {
+			o.Price = MustParseDecimal("500000.5")
+			return o
+		}, false},
+		{"post only market", markets[1], func(o LimitOrder) LimitOrder {
+			o.Pair = "ETHZAR"
+			return o
+		}, false},
+	} {
+		err := test.m.ValidateLimitOrder(test.o(valid))
+		if (err == nil) != test.ok {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+		}
+	}
+}
+
+func TestMarketRounding(t *testing.T) {
+	var volume, price string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/markets") {
+			w.Write([]byte(testMarkets))
+			return
+		}
+		volume, price = r.FormValue("volume"), r.FormValue("price")
+		w.Write([]byte(`{"order_id":"BX1"}`))
+	}, WithMarketRounding())
+
+	_, err := c.PostLimitOrder(LimitOrder{Pair: "XBTZAR", Type: ASK,
+		Volume: MustParseDecimal("0.00129"), Price: MustParseDecimal("500000.5")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if volume != "0.0012" || price != "500001" {
+		t.Errorf("Expected rounded order, got volume %s price %s", volume, price)
+	}
+
+	_, err = c.PostLimitOrder(LimitOrder{Pair: "XBTZAR", Type: ASK,
+		Volume: MustParseDecimal("0.00001"), Price: MustParseDecimal("500000")})
+	if err == nil {
+		t.Errorf("Expected error for volume below minimum")
+	}
+}
+
+func TestMarketsFetchedOnce(t *testing.T) {
+	var fetches int32
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(testMarkets))
+	})
+
+	errc := make(chan error, 3)
+	for i := 0; i < cap(errc); i++ {
+		go func() {
+			_, err := c.market(context.Background(), "XBTZAR")
+			errc <- err
+		}()
+	}
+	<-started
+
+	// Callers waiting for the fetch give up when their context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.market(ctx, "ETHZAR"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+
+	close(release)
+	for i := 0; i < cap(errc); i++ {
+		if err := <-errc; err != nil {
+			t.Error(err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("Expected 1 fetch, got %d", n)
+	}
+}
+
+func TestMarketsUnknownPairCached(t *testing.T) {
+	var fetches int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write([]byte(testMarkets))
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := c.market(context.Background(), "FOOBAR"); !errors.Is(err, ErrInvalidPair) {
+			t.Errorf("Expected ErrInvalidPair, got %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("Expected 1 fetch, got %d", fetches)
+	}
+}
@@ -0,0 +1,81 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/bitx/bitx-go/metrics"
+)
+
+// idParents lists the path segments which are followed by an ID.
+var idParents = map[string]bool{
+	"orders":      true,
+	"withdrawals": true,
+	"quotes":      true,
+	"accounts":    true,
+}
+
+// endpointLabel returns path with IDs replaced, so that the number of
+// distinct endpoint labels stays small.
+func endpointLabel(path string) string {
+	parts := strings.Split(path, "/")
+	for i := 1; i < len(parts); i++ {
+		if idParents[parts[i-1]] && parts[i] != "" {
+			parts[i] = ":id"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// MetricsMiddleware returns middleware which records the following for each
+// request attempt:
+//
+//	bitx_requests_total{method,endpoint,code}           counter
+//	bitx_request_duration_seconds{method,endpoint}      histogram
+//	bitx_request_errors_total{method,endpoint,error}    counter
+//
+// The code label is the HTTP status code, or empty if no response was
+// received. The error label is the API error code, or "transport" for errors
+// without an API error code.
+func MetricsMiddleware(m metrics.Sink) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+
+			labels := metrics.Labels{
+				"method":   req.Method,
+				"endpoint": endpointLabel(req.Path),
+			}
+			var code string
+			if resp != nil {
+				code = strconv.Itoa(resp.StatusCode)
+				m.Observe("bitx_request_duration_seconds", labels,
+					resp.Latency.Seconds())
+			}
+			m.Add("bitx_requests_total", metrics.Labels{
+				"method":   req.Method,
+				"endpoint": labels["endpoint"],
+				"code":     code,
+			}, 1)
+
+			if err != nil {
+				errLabel := "transport"
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					errLabel = apiErr.Code
+					if errLabel == "" {
+						errLabel = strconv.Itoa(apiErr.StatusCode)
+					}
+				}
+				m.Add("bitx_request_errors_total", metrics.Labels{
+					"method":   req.Method,
+					"endpoint": labels["endpoint"],
+					"error":    errLabel,
+				}, 1)
+			}
+			return resp, err
+		}
+	}
+}
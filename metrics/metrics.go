@@ -0,0 +1,278 @@
+/*
+Package metrics implements a minimal metrics registry which can be exposed in
+the Prometheus text format without depending on the Prometheus client
+library.
+
+Example:
+
+	reg := metrics.NewRegistry()
+	c := bitx.NewClient(keyID, keySecret, bitx.WithMetrics(reg))
+	http.Handle("/metrics", reg)
+*/
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Labels are the dimensions of a measurement, e.g. {"pair": "XBTZAR"}.
+type Labels map[string]string
+
+// Sink receives measurements. Implement it to forward measurements to
+// another metrics system, or use a Registry.
+type Sink interface {
+	// Add increments a counter.
+	Add(name string, labels Labels, v float64)
+
+	// Set sets a gauge, replacing any function set by SetFunc.
+	Set(name string, labels Labels, v float64)
+
+	// SetFunc sets a gauge which is evaluated by calling fn whenever the
+	// metrics are collected.
+	SetFunc(name string, labels Labels, fn func() float64)
+
+	// Observe records a sample in a histogram.
+	Observe(name string, labels Labels, v float64)
+}
+
+// DefaultBuckets are the histogram bucket upper bounds, suitable for request
+// latencies in seconds.
+var DefaultBuckets = []float64{
+	.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+type kind int
+
+const (
+	counter kind = iota
+	gauge
+	histogram
+)
+
+func (k kind) String() string {
+	switch k {
+	case counter:
+		return "counter"
+	case gauge:
+		return "gauge"
+	default:
+		return "histogram"
+	}
+}
+
+type series struct {
+	labels string
+	value  float64
+	fn     func() float64
+
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type family struct {
+	kind   kind
+	series map[string]*series
+}
+
+// Registry is a Sink which stores measurements in memory and serves them in
+// the Prometheus text exposition format. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// get returns the series for name and labels, creating it if needed. It
+// returns nil if name is already registered with a different kind.
+func (r *Registry) get(name string, k kind, labels Labels) *series {
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{kind: k, series: make(map[string]*series)}
+		r.families[name] = f
+	}
+	if f.kind != k {
+		return nil
+	}
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key}
+		if k == histogram {
+			s.buckets = make([]uint64, len(DefaultBuckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (r *Registry) Add(name string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.get(name, counter, labels); s != nil {
+		s.value += v
+	}
+}
+
+func (r *Registry) Set(name string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.get(name, gauge, labels); s != nil {
+		s.value = v
+		s.fn = nil
+	}
+}
+
+func (r *Registry) SetFunc(name string, labels Labels, fn func() float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s := r.get(name, gauge, labels); s != nil {
+		s.fn = fn
+	}
+}
+
+func (r *Registry) Observe(name string, labels Labels, v float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.get(name, histogram, labels)
+	if s == nil {
+		return
+	}
+	for i, le := range DefaultBuckets {
+		if v <= le {
+			s.buckets[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// WriteTo writes all measurements to w in the Prometheus text format.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range r.snapshot() {
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.series {
+			switch f.kind {
+			case histogram:
+				for i, le := range DefaultBuckets {
+					fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name,
+						withLabel(s.labels, "le", formatFloat(le)), s.buckets[i])
+				}
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name,
+					withLabel(s.labels, "le", "+Inf"), s.count)
+				fmt.Fprintf(&b, "%s_sum%s %s\n", f.name, s.labels, formatFloat(s.sum))
+				fmt.Fprintf(&b, "%s_count%s %d\n", f.name, s.labels, s.count)
+			default:
+				v := s.value
+				if s.fn != nil {
+					v = s.fn()
+				}
+				fmt.Fprintf(&b, "%s%s %s\n", f.name, s.labels, formatFloat(v))
+			}
+		}
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+type familySnapshot struct {
+	name   string
+	kind   kind
+	series []series
+}
+
+// snapshot returns copies of all families and series, sorted by name and
+// labels. Gauge functions are not called since they may take locks of
+// their own, e.g. ones held while recording measurements.
+func (r *Registry) snapshot() []familySnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := make([]familySnapshot, 0, len(names))
+	for _, name := range names {
+		f := r.families[name]
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fs := familySnapshot{name: name, kind: f.kind}
+		for _, k := range keys {
+			s := *f.series[k]
+			s.buckets = append([]uint64(nil), s.buckets...)
+			fs.series = append(fs.series, s)
+		}
+		res = append(res, fs)
+	}
+	return res
+}
+
+// ServeHTTP serves the measurements in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteTo(w)
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels formats labels as {k1="v1",k2="v2"}, sorted by name.
+func formatLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, k, labelEscaper.Replace(labels[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// withLabel adds a label to formatted labels.
+func withLabel(labels, name, value string) string {
+	l := fmt.Sprintf(`%s="%s"`, name, value)
+	if labels == "" {
+		return "{" + l + "}"
+	}
+	return labels[:len(labels)-1] + "," + l + "}"
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Add("requests_total", Labels{"path": "/a", "code": "200"}, 1)
+	r.Add("requests_total", Labels{"code": "200", "path": "/a"}, 2)
+	r.Set("book_size", nil, 5)
+	r.SetFunc("age_seconds", Labels{"pair": "XBTZAR"}, func() float64 { return 1.5 })
+	r.Observe("latency_seconds", nil, 0.2)
+	r.Observe("latency_seconds", nil, 20)
+
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+
+	for _, line := range []string{
+		"# TYPE requests_total counter",
+		`requests_total{code="200",path="/a"} 3`,
+		"# TYPE book_size gauge",
+		"book_size 5",
+		`age_seconds{pair="XBTZAR"} 1.5`,
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{le="0.1"} 0`,
+		`latency_seconds_bucket{le="0.25"} 1`,
+		`latency_seconds_bucket{le="+Inf"} 2`,
+		"latency_seconds_sum 20.2",
+		"latency_seconds_count 2",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in output:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package bitx
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bitx/bitx-go/metrics"
+)
+
+func TestEndpointLabel(t *testing.T) {
+	tests := map[string]string{
+		"/api/1/ticker":                    "/api/1/ticker",
+		"/api/1/orders/BXMC2CJ7HNB88U4":    "/api/1/orders/:id",
+		"/api/1/accounts/123/transactions": "/api/1/accounts/:id/transactions",
+	}
+	for in, exp := range tests {
+		if got := endpointLabel(in); got != exp {
+			t.Errorf("endpointLabel(%q) = %q, expected %q", in, got, exp)
+		}
+	}
+}
+
+func TestWithMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}, WithMetrics(reg))
+	c.GetOrder("BXMC2CJ7HNB88U4")
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	for _, line := range []string{
+		`bitx_requests_total{code="404",endpoint="/api/1/orders/:id",method="GET"} 1`,
+		`bitx_request_errors_total{endpoint="/api/1/orders/:id",error="404",method="GET"} 1`,
+		`bitx_request_duration_seconds_count{endpoint="/api/1/orders/:id",method="GET"} 1`,
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("Expected %q in output:\n%s", line, b.String())
+		}
+	}
+}
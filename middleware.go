@@ -0,0 +1,70 @@
+package bitx
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Request describes a single attempt at an API request, as seen by
+// middleware. Retried requests are passed through the middleware again with
+// an incremented Attempt.
+type Request struct {
+	Method string
+	Path   string
+
+	// Params holds a copy of the request parameters with sensitive values
+	// redacted. Changes to it are not sent.
+	Params url.Values
+
+	// Header holds additional headers to send with the request. Middleware
+	// may modify it. The Authorization, User-Agent and Content-Type headers
+	// are set by the client.
+	Header http.Header
+
+	// Attempt is 1 for the first attempt and increases with each retry.
+	Attempt int
+
+	params url.Values
+}
+
+// Response describes the result of a request attempt.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Latency is the time from sending the request until the response body
+	// was read.
+	Latency time.Duration
+}
+
+// Handler performs a request attempt. If the API rejects the request, the
+// error is an *APIError and the response is also returned.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler. It can inspect or modify the request before
+// calling next, inspect the response and error afterwards, or short-circuit
+// the request by returning without calling next.
+type Middleware func(next Handler) Handler
+
+var sensitiveParams = []string{"secret", "password", "pin", "token", "otp"}
+
+// redactParams returns a copy of params with the values of sensitive
+// parameters replaced.
+func redactParams(params url.Values) url.Values {
+	r := make(url.Values, len(params))
+	for k, v := range params {
+		r[k] = append([]string(nil), v...)
+		lk := strings.ToLower(k)
+		for _, s := range sensitiveParams {
+			if strings.Contains(lk, s) {
+				r[k] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	return r
+}
@@ -0,0 +1,81 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var gotHeader string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Trace")
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	var seen *Request
+	var seenStatus int
+	var seenErr error
+	mw := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			req.Header.Set("X-Trace", "abc")
+			seen = req
+			resp, err := next(ctx, req)
+			if resp != nil {
+				seenStatus = resp.StatusCode
+			}
+			seenErr = err
+			return resp, err
+		}
+	}
+
+	c := newTestClient(t, h, WithMiddleware(mw))
+	_, err := c.GetOrder("BXMC2CJ7HNB88U4")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if gotHeader != "abc" {
+		t.Errorf("Expected header to be sent, got %q", gotHeader)
+	}
+	if seen.Method != "GET" || seen.Path != "/api/1/orders/BXMC2CJ7HNB88U4" {
+		t.Errorf("Unexpected request: %+v", seen)
+	}
+	if seenStatus != http.StatusNotFound || seenErr != err {
+		t.Errorf("Unexpected response: %d %v", seenStatus, seenErr)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not be sent")
+	}
+	mw := func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return &Response{
+				StatusCode: http.StatusOK,
+				Body:       []byte(`{"order_id":"BXCACHED"}`),
+			}, nil
+		}
+	}
+	c := newTestClient(t, h, WithMiddleware(mw))
+	id, err := c.PostOrder("XBTZAR", BID, 1, 1, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "BXCACHED" {
+		t.Errorf("Expected BXCACHED, got %s", id)
+	}
+}
+
+func TestRedactParams(t *testing.T) {
+	p := url.Values{"pair": {"XBTZAR"}, "api_key_secret": {"s3cret"}}
+	r := redactParams(p)
+	if r.Get("pair") != "XBTZAR" || r.Get("api_key_secret") != "REDACTED" {
+		t.Errorf("Unexpected redacted params: %v", r)
+	}
+	if p.Get("api_key_secret") != "s3cret" {
+		t.Errorf("Original params were modified")
+	}
+}
@@ -0,0 +1,118 @@
+package bitx
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bitx/bitx-go/metrics"
+)
+
+type ClientOption func(*Client)
+
+// WithHTTPClient returns an option which makes the client send all requests
+// using hc instead of a private http.Client.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithTimeout returns an option which sets a time limit for each request,
+// including reading the response body. A zero timeout means no timeout.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithUserAgentSuffix returns an option which appends s to the User-Agent
+// header sent with each request.
+func WithUserAgentSuffix(s string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent + " " + s
+	}
+}
+
+// WithBaseURL returns an option which sets the URL that API paths are
+// resolved against.
+func WithBaseURL(u url.URL) ClientOption {
+	return func(c *Client) {
+		c.baseURL = u
+	}
+}
+
+// WithRetryPolicy returns an option which sets the policy for retrying
+// requests that fail with a transient error. Pass the zero RetryPolicy to
+// disable retries.
+func WithRetryPolicy(p RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// WithRateLimits returns an option which paces requests so that each class
+// of endpoints stays within its limit. Classes without a limit, or with a
+// non-positive rate, are not limited. Use DefaultRateLimits to stay within
+// Luno's published limits.
+func WithRateLimits(limits map[EndpointClass]RateLimit) ClientOption {
+	return func(c *Client) {
+		c.limiters = make(map[EndpointClass]*tokenBucket)
+		for class, l := range limits {
+			if l.Rate > 0 {
+				c.limiters[class] = newTokenBucket(l)
+			}
+		}
+	}
+}
+
+// WithRateLimitFailFast returns an option which makes requests fail with an
+// error matching ErrRateLimited instead of waiting when a rate limit is
+// exhausted. Such failures are not retried.
+func WithRateLimitFailFast() ClientOption {
+	return func(c *Client) {
+		c.rateLimitFailFast = true
+	}
+}
+
+// WithLenientDecoding returns an option which makes the client decode
+// malformed or empty numeric fields in responses as zero instead of failing
+// with a *ParseError.
+func WithLenientDecoding() ClientOption {
+	return func(c *Client) {
+		c.lenientDecoding = true
+	}
+}
+
+// WithMarketValidation returns an option which makes PostLimitOrder and
+// PostOrder check orders against the market's trading rules before posting
+// them. The rules are fetched with Markets and cached for a few minutes.
+func WithMarketValidation() ClientOption {
+	return func(c *Client) {
+		c.validateMarkets = true
+	}
+}
+
+// WithMarketRounding returns an option which makes PostLimitOrder and
+// PostOrder round orders with MarketInfo.RoundLimitOrder and then check them
+// like WithMarketValidation.
+func WithMarketRounding() ClientOption {
+	return func(c *Client) {
+		c.roundToMarkets = true
+	}
+}
+
+// WithMiddleware returns an option which adds middleware to the client.
+// The first middleware is the outermost, so it sees each request first and
+// each response last.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// WithMetrics returns an option which records request counts, latencies and
+// errors in m. See MetricsMiddleware for the metrics recorded.
+func WithMetrics(m metrics.Sink) ClientOption {
+	return WithMiddleware(MetricsMiddleware(m))
+}
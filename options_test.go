@@ -0,0 +1,42 @@
+package bitx
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWithHTTPClient(t *testing.T) {
+	hc := &http.Client{}
+	c := NewClient("", "", WithHTTPClient(hc))
+	if c.httpClient != hc {
+		t.Errorf("Expected the given http.Client to be used")
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	hc := &http.Client{}
+	c := NewClient("", "", WithHTTPClient(hc), WithTimeout(time.Second))
+	if c.httpClient.Timeout != time.Second {
+		t.Errorf("Expected timeout %s, got %s", time.Second, c.httpClient.Timeout)
+	}
+	if hc.Timeout != 0 {
+		t.Errorf("Expected caller's http.Client to be unmodified")
+	}
+}
+
+func TestWithUserAgentSuffix(t *testing.T) {
+	c := NewClient("", "", WithUserAgentSuffix("mybot/1.0"))
+	if c.userAgent != userAgent+" mybot/1.0" {
+		t.Errorf("Unexpected user agent: %q", c.userAgent)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	u := url.URL{Scheme: "http", Host: "localhost:8080"}
+	c := NewClient("", "", WithBaseURL(u))
+	if c.baseURL != u {
+		t.Errorf("Expected base URL %v, got %v", u, c.baseURL)
+	}
+}
@@ -0,0 +1,167 @@
+package bitx
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ordersPageSize is the maximum number of orders the API returns per page.
+const ordersPageSize = 1000
+
+// OrdersQuery selects the orders returned by OrdersIter. All fields are
+// optional.
+type OrdersQuery struct {
+	Pair  string
+	State OrderState
+
+	// Only orders created before CreatedBefore and at or after CreatedAfter
+	// are returned.
+	CreatedBefore time.Time
+	CreatedAfter  time.Time
+
+	// Limit is the maximum number of orders to return. Zero means no limit.
+	Limit int
+}
+
+// OrderIterator walks through orders from newest to oldest, fetching pages
+// as needed. Use it like this:
+//
+//	it := c.OrdersIter(bitx.OrdersQuery{Pair: "XBTZAR"})
+//	for it.Next() {
+//		o := it.Order()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type OrderIterator struct {
+	c   *Client
+	ctx context.Context
+	q   OrdersQuery
+
+	before time.Time
+	seen   map[string]bool
+	page   []Order
+	order  Order
+	n      int
+	last   bool
+	err    error
+}
+
+// OrdersIter returns an iterator over all orders matching q, unlike
+// ListOrders which only returns the most recent orders.
+func (c *Client) OrdersIter(q OrdersQuery) *OrderIterator {
+	return c.OrdersIterContext(context.Background(), q)
+}
+
+// OrdersIterContext is like OrdersIter but takes a context which is used for
+// all requests made by the iterator.
+func (c *Client) OrdersIterContext(ctx context.Context,
+	q OrdersQuery) *OrderIterator {
+	return &OrderIterator{c: c, ctx: ctx, q: q, before: q.CreatedBefore}
+}
+
+// Next advances to the next order. It returns false when there are no more
+// orders or an error occurred.
+func (it *OrderIterator) Next() bool {
+	if it.err != nil || (it.q.Limit > 0 && it.n >= it.q.Limit) {
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.order = it.page[0]
+	it.page = it.page[1:]
+	if !it.q.CreatedAfter.IsZero() && it.order.CreatedAt.Before(it.q.CreatedAfter) {
+		it.page = nil
+		it.last = true
+		return false
+	}
+	it.n++
+	return true
+}
+
+// Order returns the current order.
+func (it *OrderIterator) Order() Order {
+	return it.order
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *OrderIterator) Err() error {
+	return it.err
+}
+
+func (it *OrderIterator) fetch() error {
+	params := url.Values{"limit": {strconv.Itoa(ordersPageSize)}}
+	if it.q.Pair != "" {
+		params.Set("pair", it.q.Pair)
+	}
+	if it.q.State != "" {
+		params.Set("state", string(it.q.State))
+	}
+	if !it.before.IsZero() {
+		// Orders are only ordered to the millisecond, so include the
+		// millisecond of the oldest order seen so far and skip the orders
+		// which were already returned.
+		ms := it.before.UnixNano() / int64(time.Millisecond)
+		if it.seen != nil {
+			ms++
+		}
+		params.Set("created_before", strconv.FormatInt(ms, 10))
+	}
+
+	var r orders
+	err := it.c.call(it.ctx, "GET", "/api/exchange/2/listorders", params, &r)
+	if err != nil {
+		return err
+	}
+	if r.Error != "" {
+		return remoteError("/api/exchange/2/listorders", r.Error)
+	}
+	if len(r.Orders) < ordersPageSize {
+		it.last = true
+	}
+
+	d := it.c.newDecoder("/api/exchange/2/listorders")
+	var page []Order
+	for _, bo := range r.Orders {
+		o := parseOrder(d, bo)
+		if !it.seen[o.Id] {
+			page = append(page, o)
+		}
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if len(page) == 0 {
+		// No progress is possible.
+		it.last = true
+		return nil
+	}
+
+	oldest := page[len(page)-1].CreatedAt
+	seen := make(map[string]bool)
+	if oldest.Equal(it.before) {
+		for id := range it.seen {
+			seen[id] = true
+		}
+	}
+	for _, o := range page {
+		if o.CreatedAt.Equal(oldest) {
+			seen[o.Id] = true
+		}
+	}
+	it.before = oldest
+	it.seen = seen
+	it.page = page
+	return nil
+}
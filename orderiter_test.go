@@ -0,0 +1,59 @@
+package bitx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestOrdersIter(t *testing.T) {
+	// 2500 orders, newest first, with several orders per millisecond so
+	// that page boundaries fall within a millisecond.
+	const total = 2500
+	ts := func(i int) int64 { return int64(10000 - i/3) }
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		before := int64(1 << 62)
+		if s := r.URL.Query().Get("created_before"); s != "" {
+			before, _ = strconv.ParseInt(s, 10, 64)
+		}
+		var entries []string
+		for i := 0; i < total && len(entries) < ordersPageSize; i++ {
+			if ts(i) >= before {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf(`{"order_id":"BX%d",`+
+				`"creation_timestamp":%d,"state":"COMPLETE","type":"BID",`+
+				`"limit_price":"1","limit_volume":"1","base":"1",`+
+				`"counter":"1","fee_base":"0","fee_counter":"0"}`, i, ts(i)))
+		}
+		fmt.Fprintf(w, `{"orders":[%s]}`, strings.Join(entries, ","))
+	})
+
+	it := c.OrdersIter(OrdersQuery{Pair: "XBTZAR"})
+	seen := make(map[string]bool)
+	for it.Next() {
+		id := it.Order().Id
+		if seen[id] {
+			t.Errorf("Duplicate order %s", id)
+		}
+		seen[id] = true
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != total {
+		t.Errorf("Expected %d orders, got %d", total, len(seen))
+	}
+
+	it = c.OrdersIter(OrdersQuery{Limit: 5})
+	var n int
+	for it.Next() {
+		n++
+	}
+	if n != 5 {
+		t.Errorf("Expected 5 orders, got %d", n)
+	}
+}
@@ -0,0 +1,440 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+const BUY = OrderType("BUY")
+const SELL = OrderType("SELL")
+
+// MarketOrder describes an order which trades immediately at the best
+// available prices.
+type MarketOrder struct {
+	Pair string
+
+	// Type is BUY or SELL.
+	Type OrderType
+
+	// CounterVolume is the amount of the counter currency to spend. It is
+	// required for BUY orders.
+	CounterVolume Decimal
+
+	// BaseVolume is the amount of the base currency to sell. It is required
+	// for SELL orders.
+	BaseVolume Decimal
+
+	// Leave BaseAccountID and CounterAccountID empty to use your default
+	// accounts.
+	BaseAccountID, CounterAccountID string
+
+	// ClientOrderID is an optional unique id chosen by the caller.
+	ClientOrderID string
+}
+
+func (o MarketOrder) validate() error {
+	if o.Pair == "" {
+		return errors.New("bitx: market order requires a pair")
+	}
+	switch o.Type {
+	case BUY:
+		if o.CounterVolume.Sign() <= 0 {
+			return errors.New("bitx: BUY market order requires a positive counter volume")
+		}
+		if !o.BaseVolume.IsZero() {
+			return errors.New("bitx: BUY market order can't have a base volume")
+		}
+	case SELL:
+		if o.BaseVolume.Sign() <= 0 {
+			return errors.New("bitx: SELL market order requires a positive base volume")
+		}
+		if !o.CounterVolume.IsZero() {
+			return errors.New("bitx: SELL market order can't have a counter volume")
+		}
+	default:
+		return errors.New("bitx: market order type must be BUY or SELL")
+	}
+	if o.ClientOrderID != "" && !clientOrderIDRegex.MatchString(o.ClientOrderID) {
+		return errors.New("bitx: invalid client order id")
+	}
+	return nil
+}
+
+// PostMarketOrder creates a new market order and returns its id. Use
+// GetOrder to track how it was filled.
+func (c *Client) PostMarketOrder(o MarketOrder) (string, error) {
+	return c.PostMarketOrderContext(context.Background(), o)
+}
+
+// PostMarketOrderContext is like PostMarketOrder but takes a context.
+func (c *Client) PostMarketOrderContext(ctx context.Context,
+	o MarketOrder) (string, error) {
+	if err := o.validate(); err != nil {
+		return "", err
+	}
+
+	form := make(url.Values)
+	form.Add("pair", o.Pair)
+	form.Add("type", string(o.Type))
+	if o.Type == BUY {
+		form.Add("counter_volume", o.CounterVolume.String())
+	} else {
+		form.Add("base_volume", o.BaseVolume.String())
+	}
+	if o.BaseAccountID != "" {
+		form.Add("base_account_id", o.BaseAccountID)
+	}
+	if o.CounterAccountID != "" {
+		form.Add("counter_account_id", o.CounterAccountID)
+	}
+	if o.ClientOrderID != "" {
+		form.Add("client_order_id", o.ClientOrderID)
+	}
+
+	var r postorder
+	err := c.call(ctx, "POST", "/api/1/marketorder", form, &r)
+	if err != nil {
+		return "", err
+	}
+	if r.Error != "" {
+		return "", remoteError("/api/1/marketorder", r.Error)
+	}
+
+	return r.OrderId, nil
+}
+
+// TimeInForce specifies how long a limit order remains active.
+type TimeInForce string
+
+const (
+	// GoodTillCancelled orders remain open until they are filled or
+	// stopped. This is the default.
+	GoodTillCancelled = TimeInForce("GTC")
+
+	// ImmediateOrCancel orders trade as much as possible immediately and
+	// the rest is cancelled.
+	ImmediateOrCancel = TimeInForce("IOC")
+
+	// FillOrKill orders are cancelled unless they can be filled completely
+	// and immediately.
+	FillOrKill = TimeInForce("FOK")
+)
+
+// StopDirection specifies which side of the stop price the last trade price
+// must move to for a stop-limit order to trigger.
+type StopDirection string
+
+const (
+	StopAbove             = StopDirection("ABOVE")
+	StopBelow             = StopDirection("BELOW")
+	StopRelativeLastTrade = StopDirection("RELATIVE_LAST_TRADE")
+)
+
+// LimitOrder describes an order to trade at a given price or better.
+type LimitOrder struct {
+	Pair string
+
+	// Type is BID or ASK.
+	Type OrderType
+
+	Volume, Price Decimal
+
+	// Leave BaseAccountID and CounterAccountID empty to use your default
+	// accounts.
+	BaseAccountID, CounterAccountID string
+
+	// PostOnly orders are cancelled instead of trading immediately, which
+	// ensures that they only ever add liquidity.
+	PostOnly bool
+
+	// StopPrice and StopDirection turn the order into a stop-limit order,
+	// which is only placed once the stop price is reached.
+	StopPrice     Decimal
+	StopDirection StopDirection
+
+	// TimeInForce defaults to GoodTillCancelled.
+	TimeInForce TimeInForce
+
+	// ClientOrderID is an optional unique id chosen by the caller.
+	ClientOrderID string
+}
+
+var clientOrderIDRegex = regexp.MustCompile("^[[:alnum:]_;,.-]{1,255}$")
+
+// Validate returns an error if the order is incomplete or combines options
+// which the API will reject.
+func (o LimitOrder) Validate() error {
+	if o.Pair == "" {
+		return errors.New("bitx: limit order requires a pair")
+	}
+	if o.Type != BID && o.Type != ASK {
+		return errors.New("bitx: limit order type must be BID or ASK")
+	}
+	if o.Volume.Sign() <= 0 {
+		return errors.New("bitx: limit order requires a positive volume")
+	}
+	if o.Price.Sign() <= 0 {
+		return errors.New("bitx: limit order requires a positive price")
+	}
+
+	switch o.TimeInForce {
+	case "", GoodTillCancelled:
+	case ImmediateOrCancel, FillOrKill:
+		if o.PostOnly {
+			return errors.New("bitx: post-only orders can't be " +
+				string(o.TimeInForce))
+		}
+	default:
+		return errors.New("bitx: unknown time in force " +
+			string(o.TimeInForce))
+	}
+
+	switch o.StopDirection {
+	case "":
+		if !o.StopPrice.IsZero() {
+			return errors.New("bitx: stop price requires a stop direction")
+		}
+	case StopAbove, StopBelow, StopRelativeLastTrade:
+		if o.StopPrice.Sign() <= 0 {
+			return errors.New("bitx: stop direction requires a positive stop price")
+		}
+	default:
+		return errors.New("bitx: unknown stop direction " +
+			string(o.StopDirection))
+	}
+
+	if o.ClientOrderID != "" && !clientOrderIDRegex.MatchString(o.ClientOrderID) {
+		return errors.New("bitx: invalid client order id")
+	}
+	return nil
+}
+
+// PostLimitOrder validates and creates a new limit order and returns its id.
+func (c *Client) PostLimitOrder(o LimitOrder) (string, error) {
+	return c.PostLimitOrderContext(context.Background(), o)
+}
+
+// PostLimitOrderContext is like PostLimitOrder but takes a context.
+func (c *Client) PostLimitOrderContext(ctx context.Context,
+	o LimitOrder) (string, error) {
+	o, err := c.applyMarketRules(ctx, o)
+	if err != nil {
+		return "", err
+	}
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+
+	form := make(url.Values)
+	form.Add("volume", o.Volume.String())
+	form.Add("price", o.Price.String())
+	form.Add("pair", o.Pair)
+	form.Add("type", string(o.Type))
+	if o.BaseAccountID != "" {
+		form.Add("base_account_id", o.BaseAccountID)
+	}
+	if o.CounterAccountID != "" {
+		form.Add("counter_account_id", o.CounterAccountID)
+	}
+	if o.PostOnly {
+		form.Add("post_only", "true")
+	}
+	if o.StopDirection != "" {
+		form.Add("stop_price", o.StopPrice.String())
+		form.Add("stop_direction", string(o.StopDirection))
+	}
+	if o.TimeInForce != "" {
+		form.Add("time_in_force", string(o.TimeInForce))
+	}
+	if o.ClientOrderID != "" {
+		form.Add("client_order_id", o.ClientOrderID)
+	}
+
+	var r postorder
+	err = c.call(ctx, "POST", "/api/1/postorder", form, &r)
+	if err != nil {
+		return "", err
+	}
+	if r.Error != "" {
+		return "", remoteError("/api/1/postorder", r.Error)
+	}
+
+	return r.OrderId, nil
+}
+
+type orderV3 struct {
+	OrderID           string `json:"order_id"`
+	ClientOrderID     string `json:"client_order_id"`
+	Status            string `json:"status"`
+	Side              string `json:"side"`
+	Type              string `json:"type"`
+	CreationTimestamp int64  `json:"creation_timestamp"`
+	LimitPrice        string `json:"limit_price"`
+	LimitVolume       string `json:"limit_volume"`
+	Base              string `json:"base"`
+	Counter           string `json:"counter"`
+	FeeBase           string `json:"fee_base"`
+	FeeCounter        string `json:"fee_counter"`
+
+	Pair                string `json:"pair"`
+	CompletedTimestamp  int64  `json:"completed_timestamp"`
+	ExpirationTimestamp int64  `json:"expiration_timestamp"`
+	StopPrice           string `json:"stop_price"`
+	StopDirection       string `json:"stop_direction"`
+	TimeInForce         string `json:"time_in_force"`
+	BaseAccountID       string `json:"base_account_id"`
+	CounterAccountID    string `json:"counter_account_id"`
+}
+
+// toOrder converts the order to the representation used by the older API
+// endpoints.
+func (r orderV3) toOrder() order {
+	typ := r.Side
+	if r.Type != "MARKET" {
+		// Limit orders are BID or ASK.
+		if r.Side == "BUY" {
+			typ = string(BID)
+		} else if r.Side == "SELL" {
+			typ = string(ASK)
+		}
+	}
+	return order{
+		OrderId:           r.OrderID,
+		CreationTimestamp: r.CreationTimestamp,
+		Type:              typ,
+		State:             r.Status,
+		LimitPrice:        r.LimitPrice,
+		LimitVolume:       r.LimitVolume,
+		Base:              r.Base,
+		Counter:           r.Counter,
+		FeeBase:           r.FeeBase,
+		FeeCounter:        r.FeeCounter,
+
+		Pair:                r.Pair,
+		CompletedTimestamp:  r.CompletedTimestamp,
+		ExpirationTimestamp: r.ExpirationTimestamp,
+		StopPrice:           r.StopPrice,
+		StopDirection:       r.StopDirection,
+		TimeInForce:         r.TimeInForce,
+		ClientOrderID:       r.ClientOrderID,
+		BaseAccountID:       r.BaseAccountID,
+		CounterAccountID:    r.CounterAccountID,
+	}
+}
+
+// GetOrderByClientID gets an order by the client order id it was placed
+// with. It returns an error matching ErrNotFound if there is no such order.
+func (c *Client) GetOrderByClientID(clientOrderID string) (*Order, error) {
+	return c.GetOrderByClientIDContext(context.Background(), clientOrderID)
+}
+
+// GetOrderByClientIDContext is like GetOrderByClientID but takes a context.
+func (c *Client) GetOrderByClientIDContext(ctx context.Context,
+	clientOrderID string) (*Order, error) {
+	if !clientOrderIDRegex.MatchString(clientOrderID) {
+		return nil, errors.New("bitx: invalid client order id")
+	}
+	var r orderV3
+	params := url.Values{"client_order_id": {clientOrderID}}
+	err := c.call(ctx, "GET", "/api/exchange/3/order", params, &r)
+	if err != nil {
+		return nil, err
+	}
+	d := c.newDecoder("/api/exchange/3/order")
+	o := parseOrder(d, r.toOrder())
+	if d.err != nil {
+		return nil, d.err
+	}
+	return &o, nil
+}
+
+// PostLimitOrderIdempotent places the order unless an order with the same
+// client order id already exists, and returns the order's id. It is safe to
+// call repeatedly, e.g. after a timeout, without risking a duplicate order.
+// Transient failures are retried according to the client's retry policy.
+//
+// The order must have a ClientOrderID.
+func (c *Client) PostLimitOrderIdempotent(o LimitOrder) (string, error) {
+	return c.PostLimitOrderIdempotentContext(context.Background(), o)
+}
+
+// PostLimitOrderIdempotentContext is like PostLimitOrderIdempotent but takes
+// a context.
+func (c *Client) PostLimitOrderIdempotentContext(ctx context.Context,
+	o LimitOrder) (string, error) {
+	if o.ClientOrderID == "" {
+		return "", errors.New("bitx: idempotent order requires a client order id")
+	}
+	// Fail before placeOnce so that local errors never trigger a lookup.
+	o, err := c.applyMarketRules(ctx, o)
+	if err != nil {
+		return "", err
+	}
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	return c.placeOnce(ctx, o.ClientOrderID, func(ctx context.Context) (string, error) {
+		return c.PostLimitOrderContext(ctx, o)
+	})
+}
+
+// PostMarketOrderIdempotent is like PostLimitOrderIdempotent but for market
+// orders.
+func (c *Client) PostMarketOrderIdempotent(o MarketOrder) (string, error) {
+	return c.PostMarketOrderIdempotentContext(context.Background(), o)
+}
+
+// PostMarketOrderIdempotentContext is like PostMarketOrderIdempotent but
+// takes a context.
+func (c *Client) PostMarketOrderIdempotentContext(ctx context.Context,
+	o MarketOrder) (string, error) {
+	if o.ClientOrderID == "" {
+		return "", errors.New("bitx: idempotent order requires a client order id")
+	}
+	if err := o.validate(); err != nil {
+		return "", err
+	}
+	return c.placeOnce(ctx, o.ClientOrderID, func(ctx context.Context) (string, error) {
+		return c.PostMarketOrderContext(ctx, o)
+	})
+}
+
+// placeOnce calls post until it succeeds, fails permanently, or an order
+// with the client order id turns out to exist.
+func (c *Client) placeOnce(ctx context.Context, clientOrderID string,
+	post func(context.Context) (string, error)) (string, error) {
+	for attempt := 1; ; attempt++ {
+		id, err := post(ctx)
+		if err == nil {
+			return id, nil
+		} else if ctx.Err() != nil {
+			return "", err
+		}
+
+		// If the outcome is unknown or the order already exists, look it
+		// up.
+		if errors.Is(err, ErrDuplicateClientOrderID) || isTransient(err) {
+			o, lerr := c.GetOrderByClientIDContext(ctx, clientOrderID)
+			if lerr == nil {
+				return o.Id, nil
+			}
+			if !errors.Is(lerr, ErrNotFound) {
+				return "", err
+			}
+		}
+
+		if !isTransient(err) || attempt >= c.retry.MaxAttempts {
+			return "", err
+		}
+		t := time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		case <-t.C:
+		}
+	}
+}
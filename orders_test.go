@@ -0,0 +1,230 @@
+package bitx
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPostMarketOrder(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/marketorder" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("type") != "BUY" ||
+			r.Form.Get("counter_volume") != "100.50" ||
+			r.Form.Get("base_volume") != "" {
+			t.Errorf("Unexpected form: %v", r.Form)
+		}
+		w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4"}`))
+	})
+
+	id, err := c.PostMarketOrder(MarketOrder{
+		Pair:          "XBTZAR",
+		Type:          BUY,
+		CounterVolume: MustParseDecimal("100.50"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "BXMC2CJ7HNB88U4" {
+		t.Errorf("Unexpected order id %s", id)
+	}
+
+	_, err = c.PostMarketOrder(MarketOrder{
+		Pair:          "XBTZAR",
+		Type:          SELL,
+		CounterVolume: MustParseDecimal("100.50"),
+	})
+	if err == nil {
+		t.Errorf("Expected validation error")
+	}
+}
+
+func TestLimitOrderValidate(t *testing.T) {
+	valid := LimitOrder{
+		Pair:   "XBTZAR",
+		Type:   BID,
+		Volume: MustParseDecimal("0.00000001"),
+		Price:  MustParseDecimal("1000000"),
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid order, got %v", err)
+	}
+
+	tests := []func(o *LimitOrder){
+		func(o *LimitOrder) { o.Type = BUY },
+		func(o *LimitOrder) { o.Volume = Decimal{} },
+		func(o *LimitOrder) { o.PostOnly = true; o.TimeInForce = FillOrKill },
+		func(o *LimitOrder) { o.TimeInForce = "DAY" },
+		func(o *LimitOrder) { o.StopPrice = MustParseDecimal("900000") },
+		func(o *LimitOrder) { o.StopDirection = StopBelow },
+		func(o *LimitOrder) { o.ClientOrderID = "has spaces" },
+	}
+	for i, fn := range tests {
+		o := valid
+		fn(&o)
+		if err := o.Validate(); err == nil {
+			t.Errorf("Test %d: expected validation error", i)
+		}
+	}
+}
+
+func TestPostLimitOrder(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("volume") != "0.00000001" ||
+			r.Form.Get("post_only") != "true" ||
+			r.Form.Get("client_order_id") != "my-order-1" {
+			t.Errorf("Unexpected form: %v", r.Form)
+		}
+		w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4"}`))
+	})
+	_, err := c.PostLimitOrder(LimitOrder{
+		Pair:          "XBTZAR",
+		Type:          ASK,
+		Volume:        MustParseDecimal("0.00000001"),
+		Price:         MustParseDecimal("1000000"),
+		PostOnly:      true,
+		ClientOrderID: "my-order-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostLimitOrderIdempotent(t *testing.T) {
+	var posts int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/1/postorder":
+			posts++
+			if posts == 1 {
+				// The order is placed but the response is lost.
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error_code":"ErrDuplicateClientOrderID"}`))
+		case "/api/exchange/3/order":
+			if r.URL.Query().Get("client_order_id") != "my-order-1" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4",` +
+				`"client_order_id":"my-order-1","status":"PENDING",` +
+				`"side":"BUY","type":"LIMIT","limit_price":"1000000",` +
+				`"limit_volume":"1","base":"0","counter":"0",` +
+				`"fee_base":"0","fee_counter":"0"}`))
+		default:
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+	})
+
+	id, err := c.PostLimitOrderIdempotent(LimitOrder{
+		Pair:          "XBTZAR",
+		Type:          BID,
+		Volume:        MustParseDecimal("1"),
+		Price:         MustParseDecimal("1000000"),
+		ClientOrderID: "my-order-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "BXMC2CJ7HNB88U4" {
+		t.Errorf("Unexpected order id %s", id)
+	}
+	if posts != 1 {
+		t.Errorf("Expected one post, got %d", posts)
+	}
+}
+
+func TestPostLimitOrderIdempotentTimeout(t *testing.T) {
+	var posts, lookups int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/1/postorder":
+			// The order is placed but the response is too slow.
+			atomic.AddInt32(&posts, 1)
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4"}`))
+		case "/api/exchange/3/order":
+			atomic.AddInt32(&lookups, 1)
+			w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4",` +
+				`"client_order_id":"my-order-1","status":"PENDING",` +
+				`"side":"BUY","type":"LIMIT","limit_price":"1000000",` +
+				`"limit_volume":"1","base":"0","counter":"0",` +
+				`"fee_base":"0","fee_counter":"0"}`))
+		}
+	}, WithTimeout(50*time.Millisecond))
+
+	id, err := c.PostLimitOrderIdempotent(LimitOrder{
+		Pair:          "XBTZAR",
+		Type:          BID,
+		Volume:        MustParseDecimal("1"),
+		Price:         MustParseDecimal("1000000"),
+		ClientOrderID: "my-order-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, l := atomic.LoadInt32(&posts), atomic.LoadInt32(&lookups)
+	if id != "BXMC2CJ7HNB88U4" || p != 1 || l != 1 {
+		t.Errorf("Unexpected id %s after %d posts and %d lookups", id, p, l)
+	}
+}
+
+func TestPostLimitOrderIdempotentLocalError(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/exchange/1/markets" {
+			calls++
+		}
+		w.Write([]byte(testMarkets))
+	}, WithMarketValidation(), WithRetryPolicy(RetryPolicy{MaxAttempts: 3}))
+
+	_, err := c.PostLimitOrderIdempotent(LimitOrder{
+		Pair:          "FOOBAR",
+		Type:          BID,
+		Volume:        MustParseDecimal("1"),
+		Price:         MustParseDecimal("1000000"),
+		ClientOrderID: "my-order-1",
+	})
+	if !errors.Is(err, ErrInvalidPair) {
+		t.Errorf("Expected ErrInvalidPair, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("Expected no requests besides markets, got %d", calls)
+	}
+}
+
+func TestOrderHelpers(t *testing.T) {
+	o := Order{
+		State:              Complete,
+		LimitVolumeDecimal: MustParseDecimal("1.5"),
+		BaseDecimal:        MustParseDecimal("0.5"),
+		CounterDecimal:     MustParseDecimal("500000"),
+	}
+	if o.IsOpen() {
+		t.Errorf("Expected complete order not to be open")
+	}
+	if !o.IsCancelled() {
+		t.Errorf("Expected partially filled complete order to be cancelled")
+	}
+	if s := o.FilledVolume().String(); s != "0.5" {
+		t.Errorf("FilledVolume: got %s", s)
+	}
+	if s := o.RemainingVolume().String(); s != "1.0" {
+		t.Errorf("RemainingVolume: got %s", s)
+	}
+	if s := o.AveragePrice().String(); s != "1000000.00000000" {
+		t.Errorf("AveragePrice: got %s", s)
+	}
+
+	o.State = Awaiting
+	if !o.IsOpen() || o.IsCancelled() {
+		t.Errorf("Expected awaiting order to be open")
+	}
+}
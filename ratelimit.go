@@ -0,0 +1,151 @@
+package bitx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass identifies a group of API endpoints which share a rate limit
+// budget.
+type EndpointClass int
+
+const (
+	// MarketData covers the public market data endpoints such as Ticker and
+	// OrderBook.
+	MarketData EndpointClass = iota
+
+	// Trading covers order placement and order and trade queries.
+	Trading
+
+	// Funding covers balances, accounts, addresses, sends, withdrawals and
+	// quotes.
+	Funding
+)
+
+// RateLimit is a token bucket limit: Rate requests per second on average,
+// with bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimits approximates the limits published by Luno.
+var DefaultRateLimits = map[EndpointClass]RateLimit{
+	MarketData: {Rate: 1, Burst: 5},
+	Trading:    {Rate: 5, Burst: 10},
+	Funding:    {Rate: 5, Burst: 10},
+}
+
+var endpointClasses = map[string]EndpointClass{
+	"ticker":        MarketData,
+	"tickers":       MarketData,
+	"orderbook":     MarketData,
+	"orderbook_top": MarketData,
+	"trades":        MarketData,
+	"markets":       MarketData,
+	"candles":       MarketData,
+	"postorder":     Trading,
+	"marketorder":   Trading,
+	"stoporder":     Trading,
+	"listorders":    Trading,
+	"orders":        Trading,
+	"order":         Trading,
+	"listtrades":    Trading,
+	"fee_info":      Trading,
+}
+
+// endpointClass returns the class of an API path such as "/api/1/ticker" or
+// "/api/exchange/2/listorders".
+func endpointClass(path string) EndpointClass {
+	p := strings.TrimPrefix(path, "/api/")
+	p = strings.TrimPrefix(p, "exchange/")
+	if i := strings.Index(p, "/"); i >= 0 {
+		// Skip the version.
+		p = p[i+1:]
+	}
+	if i := strings.Index(p, "/"); i >= 0 {
+		p = p[:i]
+	}
+	if class, ok := endpointClasses[p]; ok {
+		return class
+	}
+	return Funding
+}
+
+// tokenBucket is a rate limiter which is safe for concurrent use.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(l RateLimit) *tokenBucket {
+	burst := float64(l.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: l.Rate, burst: burst, tokens: burst}
+}
+
+// take takes a token if one is available and otherwise returns how long to
+// wait before trying again.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// errLimiterRejected is returned when the client's own rate limiter rejects
+// a request in fail-fast mode. It matches ErrRateLimited but, unlike a 429
+// response, is never retried.
+var errLimiterRejected = fmt.Errorf("bitx: client rate limit exhausted: %w",
+	ErrRateLimited)
+
+// wait blocks until a token is available. If failFast is set, it returns
+// errLimiterRejected instead of blocking.
+func (b *tokenBucket) wait(ctx context.Context, failFast bool) error {
+	for {
+		d := b.take(time.Now())
+		if d == 0 {
+			return nil
+		}
+		if failFast {
+			return errLimiterRejected
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// waitRateLimit blocks until the rate limiter for path allows a request.
+func (c *Client) waitRateLimit(ctx context.Context, path string) error {
+	b, ok := c.limiters[endpointClass(path)]
+	if !ok {
+		return nil
+	}
+	return b.wait(ctx, c.rateLimitFailFast)
+}
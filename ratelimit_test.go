@@ -0,0 +1,84 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEndpointClass(t *testing.T) {
+	tests := []struct {
+		path string
+		exp  EndpointClass
+	}{
+		{"/api/1/ticker", MarketData},
+		{"/api/exchange/1/markets", MarketData},
+		{"/api/1/postorder", Trading},
+		{"/api/1/orders/BXMC2CJ7HNB88U4", Trading},
+		{"/api/exchange/2/listorders", Trading},
+		{"/api/1/balance", Funding},
+		{"/api/1/withdrawals/123", Funding},
+	}
+	for _, test := range tests {
+		if got := endpointClass(test.path); got != test.exp {
+			t.Errorf("endpointClass(%q) = %d, expected %d", test.path, got, test.exp)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	now := time.Now()
+	if d := b.take(now); d != 0 {
+		t.Errorf("Expected token, got wait %s", d)
+	}
+	if d := b.take(now); d != 0 {
+		t.Errorf("Expected token, got wait %s", d)
+	}
+	if d := b.take(now); d != 100*time.Millisecond {
+		t.Errorf("Expected wait of 100ms, got %s", d)
+	}
+	if d := b.take(now.Add(100 * time.Millisecond)); d != 0 {
+		t.Errorf("Expected token after refill, got wait %s", d)
+	}
+}
+
+func TestTokenBucketFailFast(t *testing.T) {
+	b := newTokenBucket(RateLimit{Rate: 1, Burst: 1})
+	ctx := context.Background()
+	if err := b.wait(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.wait(ctx, true); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+}
+
+func TestRateLimitFailFastNotRetried(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"timestamp":1,"bid":"1","ask":"2","last_trade":"1",` +
+			`"rolling_24_hour_volume":"3"}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Second}),
+		WithRateLimits(map[EndpointClass]RateLimit{
+			MarketData: {Rate: 0.001, Burst: 1}}),
+		WithRateLimitFailFast())
+
+	if _, err := c.Ticker("XBTZAR"); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := c.Ticker("XBTZAR")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("Expected to fail fast, took %v", d)
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+}
@@ -0,0 +1,155 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// replaceConfirmAttempts is the maximum number of times ReplaceOrder fetches
+// the original order while waiting for it to be stopped.
+const replaceConfirmAttempts = 5
+
+// ErrOrderStillOpen is returned by ReplaceOrder when the original order is
+// still open after being stopped.
+var ErrOrderStillOpen = errors.New("bitx: order still open after stop")
+
+// ReplaceStage is how far ReplaceOrder got.
+type ReplaceStage string
+
+const (
+	// ReplaceNotStopped means the original order may still be open and no
+	// replacement was placed.
+	ReplaceNotStopped = ReplaceStage("NOT_STOPPED")
+
+	// ReplaceStopped means the original order was stopped but placing the
+	// replacement failed, so neither order is open.
+	ReplaceStopped = ReplaceStage("STOPPED")
+
+	// ReplaceFilled means the original order was completely filled before
+	// it could be stopped, so no replacement was placed.
+	ReplaceFilled = ReplaceStage("FILLED")
+
+	// ReplacePlaced means the original order was stopped and the
+	// replacement was placed.
+	ReplacePlaced = ReplaceStage("PLACED")
+)
+
+// ReplaceResult describes the outcome of ReplaceOrder.
+type ReplaceResult struct {
+	Stage ReplaceStage
+
+	// Original is the last known state of the original order. It is nil if
+	// the order couldn't be fetched.
+	Original *Order
+
+	// Replacement is the order that was posted, or would have been posted,
+	// with its volume set. It is only valid from stage ReplaceStopped.
+	Replacement LimitOrder
+
+	// NewOrderID is the id of the replacement order, if it was placed.
+	NewOrderID string
+}
+
+// ReplaceOrder stops the limit order id and, once GetOrder confirms it is no
+// longer open, posts replacement in its place.
+//
+// Pair, Type, BaseAccountID and CounterAccountID default to those of the
+// original order. If Volume is zero, the replacement gets the original's
+// remaining volume, so that partial fills are accounted for. StopPrice and
+// StopDirection are not carried over, since the original may already have
+// been triggered; set them in replacement to place a stop-limit order. If
+// replacement has a ClientOrderID, it is posted with
+// PostLimitOrderIdempotent.
+//
+// The result is always returned, even with an error, and its Stage tells
+// which orders are open.
+func (c *Client) ReplaceOrder(id string, replacement LimitOrder) (*ReplaceResult, error) {
+	return c.ReplaceOrderContext(context.Background(), id, replacement)
+}
+
+// ReplaceOrderContext is like ReplaceOrder but takes a context.
+func (c *Client) ReplaceOrderContext(ctx context.Context, id string,
+	replacement LimitOrder) (*ReplaceResult, error) {
+	res := &ReplaceResult{Stage: ReplaceNotStopped}
+
+	// Stopping an order which has just completed fails, so check the state
+	// of the order before giving up on a failed stop.
+	attempts := replaceConfirmAttempts
+	stopErr := c.StopOrderContext(ctx, id)
+	if stopErr != nil {
+		attempts = 1
+	}
+	o, err := c.confirmStopped(ctx, id, attempts)
+	res.Original = o
+	if err != nil {
+		if stopErr != nil {
+			return res, stopErr
+		}
+		return res, err
+	}
+
+	remaining := o.RemainingVolume()
+	if remaining.Sign() <= 0 {
+		res.Stage = ReplaceFilled
+		return res, nil
+	}
+
+	res.Stage = ReplaceStopped
+	if replacement.Pair == "" {
+		replacement.Pair = o.Pair
+	}
+	if replacement.Type == "" {
+		replacement.Type = o.Type
+	}
+	if replacement.BaseAccountID == "" {
+		replacement.BaseAccountID = o.BaseAccountID
+	}
+	if replacement.CounterAccountID == "" {
+		replacement.CounterAccountID = o.CounterAccountID
+	}
+	if replacement.Volume.IsZero() {
+		replacement.Volume = remaining
+	}
+	res.Replacement = replacement
+
+	post := c.PostLimitOrderContext
+	if replacement.ClientOrderID != "" {
+		post = c.PostLimitOrderIdempotentContext
+	}
+	newID, err := post(ctx, replacement)
+	if err != nil {
+		return res, err
+	}
+	res.Stage = ReplacePlaced
+	res.NewOrderID = newID
+	return res, nil
+}
+
+// confirmStopped fetches the order until it is no longer open, at most
+// maxAttempts times.
+func (c *Client) confirmStopped(ctx context.Context, id string,
+	maxAttempts int) (*Order, error) {
+	var last *Order
+	for attempt := 1; ; attempt++ {
+		o, err := c.GetOrderContext(ctx, id)
+		if err == nil {
+			if !o.IsOpen() {
+				return o, nil
+			}
+			last = o
+			err = ErrOrderStillOpen
+		}
+		if attempt >= maxAttempts {
+			return last, err
+		}
+
+		t := time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return last, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package bitx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplaceOrder(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		stopStatus int
+		state      string
+		base       string
+		stage      ReplaceStage
+		volume     string
+		wantErr    bool
+	}{
+		{"partial fill", http.StatusOK, "COMPLETE", "0.3", ReplacePlaced, "0.7", false},
+		{"filled", http.StatusBadRequest, "COMPLETE", "1", ReplaceFilled, "", false},
+		{"stop failed", http.StatusBadRequest, "PENDING", "0", ReplaceNotStopped, "", true},
+		{"still open", http.StatusOK, "PENDING", "0", ReplaceNotStopped, "", true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var posted string
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasSuffix(r.URL.Path, "/stoporder"):
+					w.WriteHeader(test.stopStatus)
+					w.Write([]byte(`{"success":true}`))
+				case strings.HasSuffix(r.URL.Path, "/postorder"):
+					posted = r.FormValue("volume")
+					w.Write([]byte(`{"order_id":"BX2"}`))
+				default:
+					fmt.Fprintf(w, `{"order_id":"BX1","state":%q,`+
+						`"pair":"XBTZAR","type":"BID","limit_price":"100",`+
+						`"limit_volume":"1","base":%q,"counter":"0",`+
+						`"fee_base":"0","fee_counter":"0"}`,
+						test.state, test.base)
+				}
+			}, WithRetryPolicy(RetryPolicy{MinBackoff: time.Millisecond}))
+
+			res, err := c.ReplaceOrder("BX1",
+				LimitOrder{Price: MustParseDecimal("101")})
+			if (err != nil) != test.wantErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if res.Stage != test.stage {
+				t.Errorf("Expected stage %s, got %s", test.stage, res.Stage)
+			}
+			if posted != test.volume {
+				t.Errorf("Expected volume %q, got %q", test.volume, posted)
+			}
+			if test.name == "still open" && !errors.Is(err, ErrOrderStillOpen) {
+				t.Errorf("Expected ErrOrderStillOpen, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReplaceOrderAccounts(t *testing.T) {
+	var form url.Values
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/stoporder"):
+			w.Write([]byte(`{"success":true}`))
+		case strings.HasSuffix(r.URL.Path, "/postorder"):
+			r.ParseForm()
+			form = r.PostForm
+			w.Write([]byte(`{"order_id":"BX2"}`))
+		default:
+			w.Write([]byte(`{"order_id":"BX1","state":"COMPLETE",` +
+				`"pair":"XBTZAR","type":"BID","limit_price":"100",` +
+				`"limit_volume":"1","base":"0","counter":"0",` +
+				`"fee_base":"0","fee_counter":"0","stop_price":"90",` +
+				`"stop_direction":"BELOW","base_account_id":"123",` +
+				`"counter_account_id":"456"}`))
+		}
+	})
+
+	_, err := c.ReplaceOrder("BX1", LimitOrder{Price: MustParseDecimal("101")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("base_account_id") != "123" ||
+		form.Get("counter_account_id") != "456" {
+		t.Errorf("Expected original accounts, got %v", form)
+	}
+	if form.Get("stop_price") != "" || form.Get("stop_direction") != "" {
+		t.Errorf("Expected stop price not to be carried over, got %v", form)
+	}
+}
@@ -0,0 +1,116 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail with a transient error are
+// retried. Transient errors are network errors, 5xx responses and 429
+// responses.
+//
+// Only GET requests are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// A value less than 2 disables retries.
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry. It doubles with every
+	// further attempt, up to MaxBackoff, and random jitter of up to the same
+	// amount again is added. A Retry-After response header takes precedence,
+	// but is also capped at MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent enables retries of requests such as PostOrder and
+	// Send. Only set this if placing the same request twice is harmless.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is used by clients created without WithRetryPolicy.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  500 * time.Millisecond,
+	MaxBackoff:  30 * time.Second,
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.MinBackoff
+	if wait <= 0 {
+		wait = DefaultRetryPolicy.MinBackoff
+	}
+	for i := 1; i < attempt; i++ {
+		if wait > math.MaxInt64/4 {
+			// Doubling again could overflow with the jitter added.
+			break
+		}
+		wait = 2 * wait
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			wait = p.MaxBackoff
+			break
+		}
+	}
+	if wait > math.MaxInt64/2 {
+		wait = math.MaxInt64 / 2
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)))
+}
+
+func (c *Client) shouldRetry(ctx context.Context, method string,
+	attempt int, err error) bool {
+	if attempt >= c.retry.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if method != "GET" && !c.retry.RetryNonIdempotent {
+		return false
+	}
+	return isTransient(err)
+}
+
+// isTransient reports whether err may succeed if the request is repeated,
+// i.e. whether it is a network error, a 5xx response or a 429 response.
+func isTransient(err error) bool {
+	if errors.Is(err, errLimiterRejected) {
+		// Fail-fast callers want to know immediately.
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= 500
+	}
+	// Local errors, such as validation or decoding errors, would fail
+	// again. Timeouts are network errors, but callers must check their own
+	// context before retrying.
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date. Callers should cap the result.
+func retryAfter(h http.Header) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil && secs >= 0 {
+		if secs > math.MaxInt64/int64(time.Second) {
+			return math.MaxInt64, true
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
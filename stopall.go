@@ -0,0 +1,123 @@
+package bitx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StopAllOptions controls StopAllOrders. The zero value is valid.
+type StopAllOptions struct {
+	// Concurrency is the maximum number of orders stopped at the same time.
+	// Defaults to 4. Requests are also paced by the client's rate limiter,
+	// if any.
+	Concurrency int
+
+	// MaxAttempts is the maximum number of attempts to stop each order.
+	// Failures caused by transient errors are retried with the client's
+	// retry backoff. Defaults to 3.
+	MaxAttempts int
+
+	// DryRun lists the orders which would be stopped without stopping them.
+	DryRun bool
+}
+
+// StopResult is the outcome of stopping a single order.
+type StopResult struct {
+	Order Order
+
+	// Attempts is the number of StopOrder requests made. It is 0 for dry
+	// runs.
+	Attempts int
+
+	// Err is set if the order couldn't be stopped.
+	Err error
+}
+
+// StopAllOrders stops all open orders for pair, or for all pairs if pair is
+// empty. It returns one result per order in the order they were listed. The
+// error is only set if the open orders couldn't be listed, in which case
+// nothing is stopped.
+func (c *Client) StopAllOrders(pair string, opts StopAllOptions) ([]StopResult, error) {
+	return c.StopAllOrdersContext(context.Background(), pair, opts)
+}
+
+// StopAllOrdersContext is like StopAllOrders but takes a context.
+func (c *Client) StopAllOrdersContext(ctx context.Context, pair string,
+	opts StopAllOptions) ([]StopResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+
+	// Untriggered stop orders are AWAITING rather than PENDING. List them
+	// first so that orders triggered in the meantime are listed as PENDING.
+	var results []StopResult
+	seen := make(map[string]bool)
+	for _, state := range []OrderState{Awaiting, Pending} {
+		it := c.OrdersIterContext(ctx, OrdersQuery{Pair: pair, State: state})
+		for it.Next() {
+			if o := it.Order(); o.IsOpen() && !seen[o.Id] {
+				seen[o.Id] = true
+				results = append(results, StopResult{Order: o})
+			}
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.DryRun {
+		return results, nil
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *StopResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.stopWithRetry(ctx, r, opts.MaxAttempts)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (c *Client) stopWithRetry(ctx context.Context, r *StopResult,
+	maxAttempts int) {
+	for {
+		r.Attempts++
+		r.Err = c.StopOrderContext(ctx, r.Order.Id)
+		if r.Err == nil {
+			return
+		}
+
+		if r.Attempts > 1 {
+			// An earlier attempt may have stopped the order even though
+			// its response was lost.
+			o, err := c.GetOrderContext(ctx, r.Order.Id)
+			if err == nil && !o.IsOpen() {
+				r.Order = *o
+				r.Err = nil
+				return
+			}
+		}
+
+		if !isTransient(r.Err) || r.Attempts >= maxAttempts ||
+			ctx.Err() != nil {
+			return
+		}
+		t := time.NewTimer(c.retry.backoff(r.Attempts))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			r.Err = ctx.Err()
+			return
+		case <-t.C:
+		}
+	}
+}
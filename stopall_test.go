@@ -0,0 +1,90 @@
+package bitx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStopAllOrders(t *testing.T) {
+	var mu sync.Mutex
+	stops := make(map[string]int)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/listorders") {
+			states := []string{"PENDING", "PENDING", "PENDING", "AWAITING"}
+			state := r.URL.Query().Get("state")
+			if state != "PENDING" && state != "AWAITING" {
+				t.Errorf("Unexpected state filter %q", state)
+			}
+			var entries []string
+			for i, s := range states {
+				if s == state {
+					entries = append(entries, fmt.Sprintf(`{"order_id":"BX%d",`+
+						`"creation_timestamp":1000,"state":"%s","type":"BID",`+
+						`"limit_price":"1","limit_volume":"1","base":"0",`+
+						`"counter":"0","fee_base":"0","fee_counter":"0"}`,
+						i+1, s))
+				}
+			}
+			fmt.Fprintf(w, `{"orders":[%s]}`, strings.Join(entries, ","))
+			return
+		}
+
+		id := r.FormValue("order_id")
+		mu.Lock()
+		stops[id]++
+		n := stops[id]
+		mu.Unlock()
+		switch {
+		case id == "BX2" && n == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case id == "BX3":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"Cannot stop order","error_code":"ErrOrderCanNotBeStopped"}`))
+		default:
+			w.Write([]byte(`{"success":true}`))
+		}
+	}, WithRetryPolicy(RetryPolicy{MinBackoff: time.Millisecond}))
+
+	res, err := c.StopAllOrders("XBTZAR", StopAllOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 4 || len(stops) != 0 {
+		t.Fatalf("Expected 4 results and no stops, got %d and %d",
+			len(res), len(stops))
+	}
+
+	res, err = c.StopAllOrders("XBTZAR", StopAllOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range res {
+		switch r.Order.Id {
+		case "BX1":
+			if r.Err != nil || r.Attempts != 1 {
+				t.Errorf("Unexpected result for BX1: %+v", r)
+			}
+		case "BX2":
+			if r.Err != nil || r.Attempts != 2 {
+				t.Errorf("Unexpected result for BX2: %+v", r)
+			}
+		case "BX3":
+			if r.Err == nil || r.Attempts != 1 {
+				t.Errorf("Unexpected result for BX3: %+v", r)
+			}
+		case "BX4":
+			if r.Err != nil || r.Attempts != 1 {
+				t.Errorf("Unexpected result for BX4: %+v", r)
+			}
+		default:
+			t.Errorf("Unexpected result for %s", r.Order.Id)
+		}
+	}
+	if len(res) != 4 {
+		t.Errorf("Expected 4 results, got %d", len(res))
+	}
+}
@@ -1,5 +1,10 @@
 package streaming
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type order struct {
 	ID     string  `json:"id"`
 	Price  float64 `json:"price,string"`
@@ -16,6 +21,9 @@ type TradeUpdate struct {
 	Base    float64 `json:"base,string"`
 	Counter float64 `json:"counter,string"`
 	OrderID string  `json:"order_id"`
+
+	MakerOrderID string `json:"maker_order_id"`
+	TakerOrderID string `json:"taker_order_id"`
 }
 
 type CreateUpdate struct {
@@ -34,7 +42,26 @@ type Update struct {
 	TradeUpdates []*TradeUpdate `json:"trade_updates"`
 	CreateUpdate *CreateUpdate  `json:"create_update"`
 	DeleteUpdate *DeleteUpdate  `json:"delete_update"`
-	Timestamp    int64          `json:"timestamp"`
+	Timestamp    time.Time      `json:"timestamp"`
+}
+
+type updateAlias Update
+
+// UnmarshalJSON decodes the update's timestamp, which is sent as Unix
+// milliseconds.
+func (u *Update) UnmarshalJSON(b []byte) error {
+	raw := struct {
+		*updateAlias
+		Timestamp int64 `json:"timestamp"`
+	}{updateAlias: (*updateAlias)(u)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	u.Timestamp = time.Time{}
+	if raw.Timestamp != 0 {
+		u.Timestamp = time.Unix(0, raw.Timestamp*int64(time.Millisecond))
+	}
+	return nil
 }
 
 type credentials struct {
@@ -0,0 +1,22 @@
+package streaming
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestUpdateTimestamp(t *testing.T) {
+	var u Update
+	err := json.Unmarshal([]byte(`{"sequence":"5","timestamp":1469606400123}`), &u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Sequence != 5 {
+		t.Errorf("Expected sequence 5, got %d", u.Sequence)
+	}
+	exp := time.Unix(1469606400, 123*int64(time.Millisecond))
+	if !u.Timestamp.Equal(exp) {
+		t.Errorf("Expected timestamp %v, got %v", exp, u.Timestamp)
+	}
+}
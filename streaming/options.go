@@ -1,5 +1,10 @@
 package streaming
 
+import (
+	bitx "github.com/bitx/bitx-go"
+	"github.com/bitx/bitx-go/metrics"
+)
+
 type DialOption func(*Conn)
 
 // WithUpdateCallback returns an options which sets a callback function for
@@ -10,3 +15,37 @@ func WithUpdateCallback(fn UpdateCallback) DialOption {
 		c.updateCallback = fn
 	}
 }
+
+// WithMetrics returns an option which records the following in m:
+//
+//	bitx_stream_reconnects_total{pair}               counter
+//	bitx_stream_messages_total{pair,type}            counter
+//	bitx_stream_sequence_gaps_total{pair}            counter
+//	bitx_stream_book_orders{pair,side}               gauge
+//	bitx_stream_seconds_since_last_message{pair}     gauge
+//
+// bitx_stream_seconds_since_last_message is NaN before the first message and
+// after Close.
+func WithMetrics(m metrics.Sink) DialOption {
+	return func(c *Conn) {
+		c.metrics = m
+	}
+}
+
+// WithOrderTracker returns an option which notifies t of every order that
+// trades or is removed from the order book, so that tracked orders are
+// fetched as soon as they change. Create t with bitx.WithTrackerNotifications
+// to avoid also polling at the minimum interval.
+func WithOrderTracker(t *bitx.OrderTracker) DialOption {
+	return func(c *Conn) {
+		c.tracker = t
+	}
+}
+
+// WithCandleBuilder returns an option which adds every trade to b. Trades are
+// added at the price of the order they traded against in the order book.
+func WithCandleBuilder(b *bitx.CandleBuilder) DialOption {
+	return func(c *Conn) {
+		c.candles = b
+	}
+}
@@ -1,6 +1,15 @@
 package streaming
 
-import "testing"
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	bitx "github.com/bitx/bitx-go"
+	"github.com/bitx/bitx-go/metrics"
+)
 
 func TestWithUpdateCallback(t *testing.T) {
 	var c Conn
@@ -9,3 +18,145 @@ func TestWithUpdateCallback(t *testing.T) {
 		t.Errorf("Expected non-nil")
 	}
 }
+
+func TestWithMetrics(t *testing.T) {
+	var c Conn
+	WithMetrics(metrics.NewRegistry())(&c)
+	if c.metrics == nil {
+		t.Errorf("Expected non-nil")
+	}
+}
+
+func TestMetricsWhileScraping(t *testing.T) {
+	reg := metrics.NewRegistry()
+	c := Conn{pair: "XBTZAR", seq: 1, bids: map[string]order{},
+		asks: map[string]order{}}
+	WithMetrics(reg)(&c)
+	reg.SetFunc("bitx_stream_seconds_since_last_message",
+		metrics.Labels{"pair": c.pair}, c.secondsSinceLastMessage)
+
+	// Both sides need to run for a while for the race to show up.
+	stop := time.Now().Add(500 * time.Millisecond)
+	scraped := make(chan struct{})
+	go func() {
+		defer close(scraped)
+		for time.Now().Before(stop) {
+			reg.WriteTo(io.Discard)
+		}
+	}()
+
+	updated := make(chan error, 1)
+	go func() {
+		var seq int64
+		for i := 0; time.Now().Before(stop); i++ {
+			seq = int64(2 * i)
+			id := fmt.Sprint(i)
+			err := c.receivedUpdate(Update{Sequence: seq + 2,
+				CreateUpdate: &CreateUpdate{OrderID: id, Type: "BID",
+					Price: 100, Volume: 1}})
+			if err == nil {
+				err = c.receivedUpdate(Update{Sequence: seq + 3,
+					DeleteUpdate: &DeleteUpdate{OrderID: id}})
+			}
+			if err != nil {
+				updated <- err
+				return
+			}
+		}
+		// Skip a sequence number to record a gap.
+		c.receivedUpdate(Update{Sequence: seq + 5})
+		updated <- nil
+	}()
+
+	timeout := time.After(10 * time.Second)
+	select {
+	case <-scraped:
+	case <-timeout:
+		t.Fatal("Scraping deadlocked")
+	}
+	select {
+	case err := <-updated:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-timeout:
+		t.Fatal("Receiving updates deadlocked")
+	}
+
+	var b strings.Builder
+	reg.WriteTo(&b)
+	if !strings.Contains(b.String(),
+		`bitx_stream_sequence_gaps_total{pair="XBTZAR"} 1`) {
+		t.Errorf("Expected a sequence gap, got:\n%s", b.String())
+	}
+}
+
+func TestMetricsAfterClose(t *testing.T) {
+	reg := metrics.NewRegistry()
+	c := Conn{pair: "XBTZAR", lastMessage: time.Now()}
+	WithMetrics(reg)(&c)
+	reg.SetFunc("bitx_stream_seconds_since_last_message",
+		metrics.Labels{"pair": c.pair}, c.secondsSinceLastMessage)
+
+	const gauge = `bitx_stream_seconds_since_last_message{pair="XBTZAR"} `
+	var b strings.Builder
+	reg.WriteTo(&b)
+	if !strings.Contains(b.String(), gauge) ||
+		strings.Contains(b.String(), gauge+"NaN") {
+		t.Errorf("Expected a number, got:\n%s", b.String())
+	}
+
+	c.Close()
+	b.Reset()
+	reg.WriteTo(&b)
+	if !strings.Contains(b.String(), gauge+"NaN") {
+		t.Errorf("Expected NaN after Close, got:\n%s", b.String())
+	}
+}
+
+func TestWithOrderTracker(t *testing.T) {
+	tr := bitx.NewClient("", "").NewOrderTracker()
+	defer tr.Close()
+
+	var c Conn
+	WithOrderTracker(tr)(&c)
+	if c.tracker == nil {
+		t.Errorf("Expected non-nil")
+	}
+}
+
+func TestWithCandleBuilder(t *testing.T) {
+	b := bitx.NewCandleBuilder(time.Minute)
+	c := Conn{
+		seq:  1,
+		bids: map[string]order{"1": {ID: "1", Price: 100, Volume: 2}},
+		asks: map[string]order{},
+	}
+	WithCandleBuilder(b)(&c)
+
+	err := c.receivedUpdate(Update{
+		Sequence:     2,
+		TradeUpdates: []*TradeUpdate{{Base: 2, Counter: 200, OrderID: "1"}},
+		Timestamp:    time.Unix(60, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	candles := b.Candles()
+	if len(candles) != 1 || candles[0].Close.String() != "100" ||
+		candles[0].Volume.String() != "2" {
+		t.Errorf("Unexpected candles: %+v", candles)
+	}
+
+	// Trades without a book price or a timestamp are skipped.
+	trades := []*TradeUpdate{{Base: 1, OrderID: "2"}, {Base: 1, OrderID: "3"}}
+	c.addCandles(Update{TradeUpdates: trades, Timestamp: time.Unix(61, 0)},
+		[]tradePrice{{}, {price: 101, ok: true}})
+	c.addCandles(Update{TradeUpdates: trades},
+		[]tradePrice{{price: 99, ok: true}, {price: 99, ok: true}})
+	candles = b.Candles()
+	if len(candles) != 1 || candles[0].Low.String() != "100" ||
+		candles[0].Close.String() != "101" || candles[0].Volume.String() != "3" {
+		t.Errorf("Unexpected candles: %+v", candles)
+	}
+}
@@ -23,12 +23,14 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/bitx/bitx-go"
+	"github.com/bitx/bitx-go/metrics"
 	"golang.org/x/net/websocket"
 )
 
@@ -56,8 +58,10 @@ func flatten(m map[string]order, reverse bool) []bitx.OrderBookEntry {
 	var ol []bitx.OrderBookEntry
 	for _, o := range m {
 		ol = append(ol, bitx.OrderBookEntry{
-			Price:  o.Price,
-			Volume: o.Volume,
+			Price:         o.Price,
+			Volume:        o.Volume,
+			PriceDecimal:  bitx.NewDecimalFromFloat64(o.Price),
+			VolumeDecimal: bitx.NewDecimalFromFloat64(o.Volume),
 		})
 	}
 	if reverse {
@@ -74,6 +78,9 @@ type Conn struct {
 	keyID, keySecret string
 	pair             string
 	updateCallback   UpdateCallback
+	metrics          metrics.Sink
+	tracker          *bitx.OrderTracker
+	candles          *bitx.CandleBuilder
 
 	ws     *websocket.Conn
 	closed bool
@@ -103,6 +110,10 @@ func Dial(keyID, keySecret, pair string, opts ...DialOption) (*Conn, error) {
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.metrics != nil {
+		c.metrics.SetFunc("bitx_stream_seconds_since_last_message",
+			metrics.Labels{"pair": c.pair}, c.secondsSinceLastMessage)
+	}
 
 	go c.manageForever()
 	return c, nil
@@ -122,6 +133,9 @@ func (c *Conn) manageForever() {
 			return
 		}
 
+		if !lastAttempt.IsZero() {
+			c.count("bitx_stream_reconnects_total", nil)
+		}
 		lastAttempt = time.Now()
 		attempts++
 		if err := c.connect(); err != nil {
@@ -187,6 +201,7 @@ func (c *Conn) connect() error {
 		}
 
 		if string(data) == "\"\"" {
+			c.count("bitx_stream_messages_total", metrics.Labels{"type": "ping"})
 			c.receivedPing()
 			continue
 		}
@@ -197,6 +212,7 @@ func (c *Conn) connect() error {
 		}
 		if ob.Asks != nil || ob.Bids != nil {
 			// Received an order book.
+			c.count("bitx_stream_messages_total", metrics.Labels{"type": "orderbook"})
 			if err := c.receivedOrderBook(ob); err != nil {
 				return err
 			}
@@ -207,6 +223,7 @@ func (c *Conn) connect() error {
 		if err := json.Unmarshal(data, &u); err != nil {
 			return err
 		}
+		c.count("bitx_stream_messages_total", metrics.Labels{"type": "update"})
 		if err := c.receivedUpdate(u); err != nil {
 			return err
 		}
@@ -241,61 +258,143 @@ func (c *Conn) receivedOrderBook(ob orderBook) error {
 	}
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.lastMessage = time.Now()
 	c.seq = ob.Sequence
 	c.bids = bids
 	c.asks = asks
+	size := c.bookSize()
+	c.mu.Unlock()
+
+	c.recordBookSize(size)
 	return nil
 }
 
+var errOutOfSequence = errors.New("update received out of sequence")
+
+// receivedUpdate applies u and then records metrics. Metrics are recorded
+// without holding c.mu, since the sink may call secondsSinceLastMessage.
 func (c *Conn) receivedUpdate(u Update) error {
+	size, applied, err := c.applyUpdate(u)
+	if err == errOutOfSequence {
+		c.count("bitx_stream_sequence_gaps_total", nil)
+	}
+	if err != nil {
+		return err
+	}
+	if applied {
+		c.recordBookSize(size)
+	}
+	return nil
+}
+
+// applyUpdate applies u to the order book and returns the resulting book
+// size. It reports whether u was applied, which it isn't if it's old.
+func (c *Conn) applyUpdate(u Update) (bookSize, bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.seq == 0 {
 		// State not initialized so we can't update it.
-		return nil
+		return bookSize{}, false, nil
 	}
 
 	if u.Sequence <= c.seq {
 		// Old update. We can just discard it.
-		return nil
+		return bookSize{}, false, nil
 	}
 	if u.Sequence != c.seq+1 {
-		return errors.New("update received out of sequence")
+		return bookSize{}, false, errOutOfSequence
 	}
 
-	// Process trades
+	// Process trades. Look up their prices first since filled orders are
+	// removed from the book.
+	prices := c.tradePrices(u.TradeUpdates)
 	for _, t := range u.TradeUpdates {
 		if err := c.processTrade(*t); err != nil {
-			return err
+			return bookSize{}, false, err
 		}
 	}
 
 	// Process create
 	if u.CreateUpdate != nil {
 		if err := c.processCreate(*u.CreateUpdate); err != nil {
-			return err
+			return bookSize{}, false, err
 		}
 	}
 
 	// Process delete
 	if u.DeleteUpdate != nil {
 		if err := c.processDelete(*u.DeleteUpdate); err != nil {
-			return err
+			return bookSize{}, false, err
 		}
 	}
 
 	c.lastMessage = time.Now()
 	c.seq = u.Sequence
+	c.notifyTracker(u)
+	c.addCandles(u, prices)
 
 	if c.updateCallback != nil {
 		c.updateCallback(u)
 	}
 
-	return nil
+	return c.bookSize(), true, nil
+}
+
+// notifyTracker tells the order tracker about orders affected by u.
+func (c *Conn) notifyTracker(u Update) {
+	if c.tracker == nil {
+		return
+	}
+	for _, t := range u.TradeUpdates {
+		for _, id := range []string{t.OrderID, t.MakerOrderID, t.TakerOrderID} {
+			if id != "" {
+				c.tracker.Notify(id)
+			}
+		}
+	}
+	if u.DeleteUpdate != nil {
+		c.tracker.Notify(u.DeleteUpdate.OrderID)
+	}
+}
+
+// tradePrice is the book price of the order a trade was made against. ok is
+// false if the order wasn't in the book.
+type tradePrice struct {
+	price float64
+	ok    bool
+}
+
+// tradePrices returns the book prices of the orders that trades were made
+// against, if needed for the candle builder.
+func (c *Conn) tradePrices(trades []*TradeUpdate) []tradePrice {
+	if c.candles == nil {
+		return nil
+	}
+	prices := make([]tradePrice, len(trades))
+	for i, t := range trades {
+		if o, ok := c.bids[t.OrderID]; ok {
+			prices[i] = tradePrice{o.Price, true}
+		} else if o, ok := c.asks[t.OrderID]; ok {
+			prices[i] = tradePrice{o.Price, true}
+		}
+	}
+	return prices
+}
+
+// addCandles adds the trades in u to the candle builder, skipping those
+// without a known price or time.
+func (c *Conn) addCandles(u Update, prices []tradePrice) {
+	if c.candles == nil || u.Timestamp.IsZero() {
+		return
+	}
+	for i, t := range u.TradeUpdates {
+		if !prices[i].ok {
+			continue
+		}
+		c.candles.Add(u.Timestamp, bitx.NewDecimalFromFloat64(prices[i].price),
+			bitx.NewDecimalFromFloat64(t.Base))
+	}
 }
 
 // addD8 adds the two values and rounds the result to the nearest 8 decimal
@@ -373,6 +472,52 @@ func (c *Conn) processDelete(u DeleteUpdate) error {
 	return nil
 }
 
+// count increments a counter if metrics are enabled. The pair label is
+// added to labels. c.mu must not be held.
+func (c *Conn) count(name string, labels metrics.Labels) {
+	if c.metrics == nil {
+		return
+	}
+	l := metrics.Labels{"pair": c.pair}
+	for k, v := range labels {
+		l[k] = v
+	}
+	c.metrics.Add(name, l, 1)
+}
+
+type bookSize struct {
+	bids, asks int
+}
+
+// bookSize returns the number of orders on each side of the book. c.mu must
+// be held.
+func (c *Conn) bookSize() bookSize {
+	return bookSize{bids: len(c.bids), asks: len(c.asks)}
+}
+
+// recordBookSize records the number of orders on each side of the book.
+// c.mu must not be held.
+func (c *Conn) recordBookSize(size bookSize) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.Set("bitx_stream_book_orders",
+		metrics.Labels{"pair": c.pair, "side": "bid"}, float64(size.bids))
+	c.metrics.Set("bitx_stream_book_orders",
+		metrics.Labels{"pair": c.pair, "side": "ask"}, float64(size.asks))
+}
+
+// secondsSinceLastMessage returns NaN if no message has been received or the
+// connection is closed.
+func (c *Conn) secondsSinceLastMessage() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.lastMessage.IsZero() || c.closed {
+		return math.NaN()
+	}
+	return time.Since(c.lastMessage).Seconds()
+}
+
 // OrderBookSnapshot returns the latest order book.
 func (c *Conn) OrderBookSnapshot() (int64, []bitx.OrderBookEntry, []bitx.OrderBookEntry) {
 	c.mu.Lock()
@@ -386,10 +531,15 @@ func (c *Conn) OrderBookSnapshot() (int64, []bitx.OrderBookEntry, []bitx.OrderBo
 // Close the connection.
 func (c *Conn) Close() {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.closed = true
 	if c.ws != nil {
 		c.ws.Close()
 	}
+	c.mu.Unlock()
+
+	if c.metrics != nil {
+		// Replace the gauge function so that the sink doesn't keep c alive.
+		c.metrics.Set("bitx_stream_seconds_since_last_message",
+			metrics.Labels{"pair": c.pair}, math.NaN())
+	}
 }
@@ -0,0 +1,396 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOrderNotFilled is returned by OrderTracker.WaitFilled when the order is
+// completed without being completely filled, e.g. because it was stopped.
+var ErrOrderNotFilled = errors.New("bitx: order completed without being filled")
+
+// OrderEvent describes a change to a tracked order.
+type OrderEvent struct {
+	// Order is the latest state of the order. If Err is set, only Order.Id
+	// is valid.
+	Order Order
+
+	// Previous is the state before this event, or empty for the first event
+	// of an order.
+	Previous OrderState
+
+	// Filled is the base volume traded since the previous event.
+	Filled Decimal
+
+	// Err is set if the order couldn't be fetched.
+	Err error
+}
+
+type TrackerOption func(*OrderTracker)
+
+// WithTrackerEvents returns an option which makes the tracker send all
+// events to ch. The tracker blocks until each event is received.
+func WithTrackerEvents(ch chan<- OrderEvent) TrackerOption {
+	return func(t *OrderTracker) {
+		t.events = ch
+	}
+}
+
+// WithTrackerCallback returns an option which makes the tracker call fn with
+// each event.
+func WithTrackerCallback(fn func(OrderEvent)) TrackerOption {
+	return func(t *OrderTracker) {
+		t.callback = fn
+	}
+}
+
+// WithTrackerPollInterval returns an option which sets how often orders are
+// polled. Polling starts at min after a change and backs off to max while
+// the order is unchanged or can't be fetched.
+func WithTrackerPollInterval(min, max time.Duration) TrackerOption {
+	return func(t *OrderTracker) {
+		t.pollMin = min
+		t.pollMax = max
+	}
+}
+
+// WithTrackerNotifications returns an option for trackers which are told
+// about order changes through Notify, e.g. by streaming.WithOrderTracker.
+// Orders are then only polled at the maximum poll interval as a fallback.
+func WithTrackerNotifications() TrackerOption {
+	return func(t *OrderTracker) {
+		t.notified = true
+	}
+}
+
+type trackResult struct {
+	order Order
+	err   error
+}
+
+type waiter struct {
+	done func(Order) bool
+	ch   chan trackResult
+}
+
+type trackedOrder struct {
+	last     *Order
+	interval time.Duration
+	next     time.Time
+	waiters  []*waiter
+}
+
+// OrderTracker watches orders and reports state changes and fills. By
+// default it polls GetOrder, backing off while orders are unchanged.
+type OrderTracker struct {
+	c        *Client
+	pollMin  time.Duration
+	pollMax  time.Duration
+	notified bool
+	events   chan<- OrderEvent
+	callback func(OrderEvent)
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+	done   chan struct{}
+
+	mu     sync.Mutex
+	orders map[string]*trackedOrder
+}
+
+// NewOrderTracker starts a tracker. Call Close to stop it.
+func (c *Client) NewOrderTracker(opts ...TrackerOption) *OrderTracker {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &OrderTracker{
+		c:       c,
+		pollMin: time.Second,
+		pollMax: 30 * time.Second,
+		ctx:     ctx,
+		cancel:  cancel,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		orders:  make(map[string]*trackedOrder),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	go t.run()
+	return t
+}
+
+// Close stops the tracker. Pending waits return context.Canceled.
+func (t *OrderTracker) Close() {
+	t.cancel()
+	<-t.done
+}
+
+// Track starts tracking an order. Tracking stops automatically once the
+// order is no longer open.
+func (t *OrderTracker) Track(id string) {
+	t.mu.Lock()
+	t.track(id)
+	t.mu.Unlock()
+	t.signal()
+}
+
+// track must be called with t.mu held.
+func (t *OrderTracker) track(id string) *trackedOrder {
+	o, ok := t.orders[id]
+	if !ok {
+		o = &trackedOrder{interval: t.minInterval(), next: time.Now()}
+		t.orders[id] = o
+	}
+	return o
+}
+
+// Untrack stops tracking an order. Pending waits for it are not affected
+// and will not complete unless the order is tracked again.
+func (t *OrderTracker) Untrack(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, id)
+}
+
+// Notify tells the tracker that an order may have changed. If the order is
+// tracked, it is fetched as soon as possible.
+func (t *OrderTracker) Notify(id string) {
+	t.mu.Lock()
+	o, ok := t.orders[id]
+	if ok {
+		o.next = time.Now()
+		o.interval = t.minInterval()
+	}
+	t.mu.Unlock()
+	if ok {
+		t.signal()
+	}
+}
+
+// WaitDone tracks the order and waits until it is no longer open.
+func (t *OrderTracker) WaitDone(ctx context.Context, id string) (*Order, error) {
+	return t.wait(ctx, id, func(o Order) bool { return !o.IsOpen() })
+}
+
+// WaitFilled tracks the order and waits until it is completely filled. It
+// returns ErrOrderNotFilled if the order is completed without being filled.
+func (t *OrderTracker) WaitFilled(ctx context.Context, id string) (*Order, error) {
+	o, err := t.wait(ctx, id, isFilled)
+	if err != nil {
+		return nil, err
+	}
+	if !isFilled(*o) {
+		return o, ErrOrderNotFilled
+	}
+	return o, nil
+}
+
+func isFilled(o Order) bool {
+	if o.LimitVolumeDecimal.Sign() > 0 {
+		return o.BaseDecimal.Cmp(o.LimitVolumeDecimal) >= 0
+	}
+	// Market orders have no limit volume.
+	return o.State == Complete && o.BaseDecimal.Sign() > 0
+}
+
+// wait waits until done reports true or the order is no longer open.
+func (t *OrderTracker) wait(ctx context.Context, id string,
+	done func(Order) bool) (*Order, error) {
+	w := &waiter{done: done, ch: make(chan trackResult, 1)}
+
+	t.mu.Lock()
+	o := t.track(id)
+	if o.last != nil && (done(*o.last) || !o.last.IsOpen()) {
+		last := *o.last
+		t.mu.Unlock()
+		return &last, nil
+	}
+	o.waiters = append(o.waiters, w)
+	t.mu.Unlock()
+	t.signal()
+
+	select {
+	case r := <-w.ch:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return &r.order, nil
+	case <-ctx.Done():
+		t.removeWaiter(id, w)
+		return nil, ctx.Err()
+	case <-t.ctx.Done():
+		return nil, t.ctx.Err()
+	}
+}
+
+func (t *OrderTracker) removeWaiter(id string, w *waiter) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	o, ok := t.orders[id]
+	if !ok {
+		return
+	}
+	for i, ow := range o.waiters {
+		if ow == w {
+			o.waiters = append(o.waiters[:i], o.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *OrderTracker) minInterval() time.Duration {
+	if t.notified {
+		return t.pollMax
+	}
+	return t.pollMin
+}
+
+func (t *OrderTracker) signal() {
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (t *OrderTracker) run() {
+	defer close(t.done)
+	for {
+		for _, id := range t.due() {
+			t.refresh(id)
+		}
+
+		timer := time.NewTimer(t.nextWake())
+		select {
+		case <-t.ctx.Done():
+			timer.Stop()
+			return
+		case <-t.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// due returns the ids of orders which should be fetched now.
+func (t *OrderTracker) due() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	var ids []string
+	for id, o := range t.orders {
+		if !o.next.After(now) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// nextWake returns how long to sleep until an order is due.
+func (t *OrderTracker) nextWake() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	wait := t.pollMax
+	now := time.Now()
+	for _, o := range t.orders {
+		if d := o.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (t *OrderTracker) refresh(id string) {
+	o, err := t.c.GetOrderContext(t.ctx, id)
+	if t.ctx.Err() != nil {
+		return
+	}
+
+	t.mu.Lock()
+	tr, ok := t.orders[id]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+
+	if err != nil {
+		var waiters []*waiter
+		if errors.Is(err, ErrNotFound) || !isValidPathID(id) {
+			// The order will never show up, so give up on it.
+			waiters = tr.waiters
+			delete(t.orders, id)
+		} else {
+			t.backOff(tr)
+			tr.next = time.Now().Add(tr.interval)
+		}
+		t.mu.Unlock()
+
+		for _, w := range waiters {
+			w.ch <- trackResult{err: err}
+		}
+		t.emit(OrderEvent{Order: Order{Id: id}, Err: err})
+		return
+	}
+
+	var ev *OrderEvent
+	if tr.last == nil || tr.last.State != o.State ||
+		tr.last.BaseDecimal.Cmp(o.BaseDecimal) != 0 {
+		ev = &OrderEvent{Order: *o, Filled: o.BaseDecimal}
+		if tr.last != nil {
+			ev.Previous = tr.last.State
+			ev.Filled = o.BaseDecimal.Sub(tr.last.BaseDecimal)
+		}
+		tr.interval = t.minInterval()
+	} else {
+		t.backOff(tr)
+	}
+	tr.last = o
+	tr.next = time.Now().Add(tr.interval)
+
+	var ready []*waiter
+	pending := tr.waiters[:0]
+	for _, w := range tr.waiters {
+		if w.done(*o) || !o.IsOpen() {
+			ready = append(ready, w)
+		} else {
+			pending = append(pending, w)
+		}
+	}
+	tr.waiters = pending
+	if !o.IsOpen() {
+		delete(t.orders, id)
+	}
+	t.mu.Unlock()
+
+	for _, w := range ready {
+		w.ch <- trackResult{order: *o}
+	}
+	if ev != nil {
+		t.emit(*ev)
+	}
+}
+
+// backOff doubles the poll interval of tr, up to the maximum. t.mu must be
+// held.
+func (t *OrderTracker) backOff(tr *trackedOrder) {
+	tr.interval *= 2
+	if tr.interval > t.pollMax {
+		tr.interval = t.pollMax
+	}
+}
+
+func (t *OrderTracker) emit(ev OrderEvent) {
+	if t.callback != nil {
+		t.callback(ev)
+	}
+	if t.events != nil {
+		select {
+		case t.events <- ev:
+		case <-t.ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOrderTracker(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+
+		state, base := "PENDING", "0"
+		if n == 2 {
+			base = "0.4"
+		} else if n >= 3 {
+			state, base = "COMPLETE", "1"
+		}
+		fmt.Fprintf(w, `{"order_id":"BXMC2CJ7HNB88U4","state":%q,`+
+			`"type":"BID","limit_price":"100","limit_volume":"1",`+
+			`"base":%q,"counter":"0","fee_base":"0","fee_counter":"0"}`,
+			state, base)
+	})
+
+	var events []OrderEvent
+	tr := c.NewOrderTracker(
+		WithTrackerPollInterval(time.Millisecond, 5*time.Millisecond),
+		WithTrackerCallback(func(ev OrderEvent) { events = append(events, ev) }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	o, err := tr.WaitFilled(ctx, "BXMC2CJ7HNB88U4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Close()
+
+	if o.State != Complete {
+		t.Errorf("Expected complete order, got %s", o.State)
+	}
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(events))
+	}
+	if s := events[1].Filled.String(); s != "0.4" {
+		t.Errorf("Expected partial fill of 0.4, got %s", s)
+	}
+	if events[2].Previous != Pending || events[2].Filled.String() != "0.6" {
+		t.Errorf("Unexpected final event: %+v", events[2])
+	}
+}
+
+func TestOrderTrackerNotFilled(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"order_id":"BXMC2CJ7HNB88U4","state":"COMPLETE",` +
+			`"type":"BID","limit_price":"100","limit_volume":"1",` +
+			`"base":"0","counter":"0","fee_base":"0","fee_counter":"0"}`))
+	})
+	tr := c.NewOrderTracker()
+	defer tr.Close()
+
+	_, err := tr.WaitFilled(context.Background(), "BXMC2CJ7HNB88U4")
+	if !errors.Is(err, ErrOrderNotFilled) {
+		t.Errorf("Expected ErrOrderNotFilled, got %v", err)
+	}
+	o, err := tr.WaitDone(context.Background(), "BXMC2CJ7HNB88U4")
+	if err != nil || !o.IsCancelled() {
+		t.Errorf("Expected cancelled order, got %v %v", o, err)
+	}
+}
+
+func TestOrderTrackerErrors(t *testing.T) {
+	var mu sync.Mutex
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	tr := c.NewOrderTracker(
+		WithTrackerPollInterval(time.Millisecond, time.Second))
+	defer tr.Close()
+
+	// Local errors fail waiters at once.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := tr.WaitDone(ctx, "not/valid"); err == nil ||
+		ctx.Err() != nil {
+		t.Errorf("Expected invalid id error, got %v", err)
+	}
+
+	// Other errors are retried with backoff.
+	tr.Track("BXMC2CJ7HNB88U4")
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	n := calls
+	mu.Unlock()
+	if n < 2 || n > 20 {
+		t.Errorf("Expected polling to back off, got %d calls", n)
+	}
+}
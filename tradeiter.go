@@ -0,0 +1,128 @@
+package bitx
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TradeIterator walks forward through a market's public trades, oldest
+// first, until it reaches the latest trade. Use it like this:
+//
+//	it := c.TradesIter("XBTZAR", since)
+//	for it.Next() {
+//		t := it.Trade()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TradeIterator struct {
+	c    *Client
+	ctx  context.Context
+	pair string
+
+	since time.Time
+	seen  map[string]bool
+	page  []Trade
+	trade Trade
+	last  bool
+	err   error
+}
+
+// TradesIter returns an iterator over all trades for pair made at or after
+// since.
+func (c *Client) TradesIter(pair string, since time.Time) *TradeIterator {
+	return c.TradesIterContext(context.Background(), pair, since)
+}
+
+// TradesIterContext is like TradesIter but takes a context which is used for
+// all requests made by the iterator.
+func (c *Client) TradesIterContext(ctx context.Context, pair string,
+	since time.Time) *TradeIterator {
+	return &TradeIterator{c: c, ctx: ctx, pair: pair, since: since}
+}
+
+// Next advances to the next trade. It returns false when there are no more
+// trades or an error occurred.
+func (it *TradeIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.trade = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Trade returns the current trade.
+func (it *TradeIterator) Trade() Trade {
+	return it.trade
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *TradeIterator) Err() error {
+	return it.err
+}
+
+// tradeKey identifies a trade for de-duplication.
+func tradeKey(t Trade) string {
+	if t.Sequence != 0 {
+		return strconv.FormatInt(t.Sequence, 10)
+	}
+	return t.Timestamp.String() + " " + t.PriceDecimal.String() + " " +
+		t.VolumeDecimal.String() + " " + strconv.FormatBool(t.IsBuy)
+}
+
+func (it *TradeIterator) fetch() error {
+	trades, err := it.c.TradesSinceContext(it.ctx, it.pair, it.since)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		if !trades[i].Timestamp.Equal(trades[j].Timestamp) {
+			return trades[i].Timestamp.Before(trades[j].Timestamp)
+		}
+		return trades[i].Sequence < trades[j].Sequence
+	})
+
+	// Each page starts at the millisecond of the newest trade seen so far,
+	// so skip the trades which were already returned.
+	var page []Trade
+	for _, t := range trades {
+		if !t.Timestamp.Before(it.since) && !it.seen[tradeKey(t)] {
+			page = append(page, t)
+		}
+	}
+	if len(page) == 0 {
+		// We've caught up.
+		it.last = true
+		return nil
+	}
+
+	newest := page[len(page)-1].Timestamp
+	seen := make(map[string]bool)
+	if newest.Equal(it.since) {
+		for k := range it.seen {
+			seen[k] = true
+		}
+	}
+	for _, t := range page {
+		if t.Timestamp.Equal(newest) {
+			seen[tradeKey(t)] = true
+		}
+	}
+	it.since = newest
+	it.seen = seen
+	it.page = page
+	return nil
+}
@@ -0,0 +1,53 @@
+package bitx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTradesIter(t *testing.T) {
+	// 250 trades with several trades per millisecond so that page
+	// boundaries fall within a millisecond.
+	const total = 250
+	ts := func(i int) int64 { return int64(10000 + i/4) }
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		since, _ := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+		var entries []string
+		for i := 0; i < total && len(entries) < 100; i++ {
+			if ts(i) < since {
+				continue
+			}
+			// Newest first.
+			entries = append([]string{fmt.Sprintf(`{"sequence":%d,`+
+				`"timestamp":%d,"price":"1","volume":"1","is_buy":%t}`,
+				i+1, ts(i), i%2 == 0)}, entries...)
+		}
+		fmt.Fprintf(w, `{"trades":[%s]}`, strings.Join(entries, ","))
+	})
+
+	it := c.TradesIter("XBTZAR", time.Unix(10, 0))
+	var n int
+	var prev int64
+	for it.Next() {
+		tr := it.Trade()
+		if tr.Sequence != prev+1 {
+			t.Fatalf("Expected sequence %d, got %d", prev+1, tr.Sequence)
+		}
+		if tr.IsBuy != (n%2 == 0) {
+			t.Errorf("Unexpected taker side for trade %d", tr.Sequence)
+		}
+		prev = tr.Sequence
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n != total {
+		t.Errorf("Expected %d trades, got %d", total, n)
+	}
+}
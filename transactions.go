@@ -0,0 +1,236 @@
+package bitx
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// transactionsPageSize is the maximum number of rows the API returns per
+// request.
+const transactionsPageSize = 1000
+
+// Transaction is an entry in an account's ledger.
+type Transaction struct {
+	// RowIndex is the position of the transaction in the ledger, starting
+	// at 1. It is zero for pending transactions.
+	RowIndex  int64
+	Timestamp time.Time
+
+	// Balance and Available are the account's balances after the
+	// transaction.
+	Balance   Decimal
+	Available Decimal
+
+	BalanceDelta   Decimal
+	AvailableDelta Decimal
+
+	Currency    string
+	Description string
+}
+
+type transaction struct {
+	RowIndex       int64  `json:"row_index"`
+	Timestamp      int64  `json:"timestamp"`
+	Balance        string `json:"balance"`
+	Available      string `json:"available"`
+	BalanceDelta   string `json:"balance_delta"`
+	AvailableDelta string `json:"available_delta"`
+	Currency       string `json:"currency"`
+	Description    string `json:"description"`
+}
+
+type transactions struct {
+	Error        string        `json:"error"`
+	Transactions []transaction `json:"transactions"`
+	Pending      []transaction `json:"pending"`
+}
+
+func parseTransactions(d *decoder, txs []transaction) []Transaction {
+	res := make([]Transaction, len(txs))
+	for i, t := range txs {
+		res[i] = Transaction{
+			RowIndex:       t.RowIndex,
+			Timestamp:      fromMillis(t.Timestamp),
+			Balance:        d.decimal("balance", t.Balance),
+			Available:      d.decimal("available", t.Available),
+			BalanceDelta:   d.decimal("balance_delta", t.BalanceDelta),
+			AvailableDelta: d.decimal("available_delta", t.AvailableDelta),
+			Currency:       t.Currency,
+			Description:    t.Description,
+		}
+	}
+	return res
+}
+
+// ListTransactions returns the transactions of an account with row indexes
+// from minRow up to but excluding maxRow. At most 1000 rows can be requested
+// at a time.
+func (c *Client) ListTransactions(accountID string,
+	minRow, maxRow int64) ([]Transaction, error) {
+	return c.ListTransactionsContext(context.Background(),
+		accountID, minRow, maxRow)
+}
+
+// ListTransactionsContext is like ListTransactions but takes a context.
+func (c *Client) ListTransactionsContext(ctx context.Context,
+	accountID string, minRow, maxRow int64) ([]Transaction, error) {
+	if !isValidPathID(accountID) {
+		return nil, errors.New("invalid account id")
+	}
+	path := "/api/1/accounts/" + accountID + "/transactions"
+	params := url.Values{
+		"min_row": {strconv.FormatInt(minRow, 10)},
+		"max_row": {strconv.FormatInt(maxRow, 10)},
+	}
+	var r transactions
+	err := c.call(ctx, "GET", path, params, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, remoteError(path, r.Error)
+	}
+
+	d := c.newDecoder(path)
+	txs := parseTransactions(d, r.Transactions)
+	if d.err != nil {
+		return nil, d.err
+	}
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].RowIndex < txs[j].RowIndex
+	})
+	return txs, nil
+}
+
+// ListPendingTransactions returns the transactions of an account which have
+// not been added to its ledger yet, such as unconfirmed deposits.
+func (c *Client) ListPendingTransactions(accountID string) ([]Transaction, error) {
+	return c.ListPendingTransactionsContext(context.Background(), accountID)
+}
+
+// ListPendingTransactionsContext is like ListPendingTransactions but takes a
+// context.
+func (c *Client) ListPendingTransactionsContext(ctx context.Context,
+	accountID string) ([]Transaction, error) {
+	if !isValidPathID(accountID) {
+		return nil, errors.New("invalid account id")
+	}
+	path := "/api/1/accounts/" + accountID + "/pending"
+	var r transactions
+	err := c.call(ctx, "GET", path, nil, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, remoteError(path, r.Error)
+	}
+
+	d := c.newDecoder(path)
+	txs := parseTransactions(d, r.Pending)
+	if d.err != nil {
+		return nil, d.err
+	}
+	return txs, nil
+}
+
+// TransactionIterator walks through an account's ledger from oldest to
+// newest, fetching pages as needed. Use it like this:
+//
+//	it := c.TransactionsIter(accountID, 1)
+//	for it.Next() {
+//		tx := it.Transaction()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TransactionIterator struct {
+	c         *Client
+	ctx       context.Context
+	accountID string
+
+	row  int64
+	page []Transaction
+	tx   Transaction
+	last bool
+	err  error
+}
+
+// TransactionsIter returns an iterator over the transactions of an account,
+// starting at row index fromRow. Pass 1 to walk the full ledger.
+func (c *Client) TransactionsIter(accountID string,
+	fromRow int64) *TransactionIterator {
+	return c.TransactionsIterContext(context.Background(), accountID, fromRow)
+}
+
+// TransactionsIterContext is like TransactionsIter but takes a context which
+// is used for all requests made by the iterator.
+func (c *Client) TransactionsIterContext(ctx context.Context,
+	accountID string, fromRow int64) *TransactionIterator {
+	if fromRow < 1 {
+		fromRow = 1
+	}
+	return &TransactionIterator{c: c, ctx: ctx, accountID: accountID,
+		row: fromRow}
+}
+
+// Next advances to the next transaction. It returns false when there are no
+// more transactions or an error occurred.
+func (it *TransactionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for len(it.page) == 0 {
+		if it.last {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+	it.tx = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Transaction returns the current transaction.
+func (it *TransactionIterator) Transaction() Transaction {
+	return it.tx
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+// NextRow returns the row index of the first transaction the iterator
+// hasn't returned yet. Pass it to TransactionsIter to continue from there
+// later.
+func (it *TransactionIterator) NextRow() int64 {
+	if len(it.page) > 0 {
+		return it.page[0].RowIndex
+	}
+	return it.row
+}
+
+func (it *TransactionIterator) fetch() error {
+	txs, err := it.c.ListTransactionsContext(it.ctx, it.accountID,
+		it.row, it.row+transactionsPageSize)
+	if err != nil {
+		return err
+	}
+	if len(txs) < transactionsPageSize {
+		it.last = true
+	}
+	if len(txs) == 0 {
+		return nil
+	}
+	it.row = txs[len(txs)-1].RowIndex + 1
+	it.page = txs
+	return nil
+}
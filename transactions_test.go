@@ -0,0 +1,66 @@
+package bitx
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTransactionsIter(t *testing.T) {
+	const total = 2345
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/1/accounts/319232323/transactions" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		min, _ := strconv.Atoi(r.URL.Query().Get("min_row"))
+		max, _ := strconv.Atoi(r.URL.Query().Get("max_row"))
+		var entries []string
+		for row := min; row < max && row <= total; row++ {
+			// Newest first.
+			entries = append([]string{fmt.Sprintf(`{"row_index":%d,`+
+				`"timestamp":1469606400123,"balance":"%d","available":"%d",`+
+				`"balance_delta":"1","available_delta":"1","currency":"XBT",`+
+				`"description":"Deposit"}`, row, row, row)}, entries...)
+		}
+		fmt.Fprintf(w, `{"id":"319232323","transactions":[%s]}`,
+			strings.Join(entries, ","))
+	})
+
+	it := c.TransactionsIter("319232323", 1)
+	var row int64
+	for it.Next() {
+		tx := it.Transaction()
+		row++
+		if tx.RowIndex != row || tx.Balance.String() != strconv.FormatInt(row, 10) {
+			t.Fatalf("Unexpected transaction %+v", tx)
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if row != total {
+		t.Errorf("Expected %d transactions, got %d", total, row)
+	}
+	if it.NextRow() != total+1 {
+		t.Errorf("Expected next row %d, got %d", total+1, it.NextRow())
+	}
+}
+
+func TestListPendingTransactions(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"319232323","pending":[{"timestamp":1469606400123,` +
+			`"balance":"0","available":"0","balance_delta":"0.5",` +
+			`"available_delta":"0","currency":"XBT",` +
+			`"description":"Unconfirmed deposit"}]}`))
+	})
+	txs, err := c.ListPendingTransactions("319232323")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].BalanceDelta.String() != "0.5" ||
+		txs[0].Currency != "XBT" {
+		t.Errorf("Unexpected transactions %+v", txs)
+	}
+}